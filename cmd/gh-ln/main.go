@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
@@ -9,7 +11,9 @@ import (
 	"github.com/nobe4/gh-ln/internal/flags"
 	handler "github.com/nobe4/gh-ln/internal/log"
 	"github.com/nobe4/gh-ln/pkg/client"
+	"github.com/nobe4/gh-ln/pkg/client/etag"
 	"github.com/nobe4/gh-ln/pkg/client/noop"
+	"github.com/nobe4/gh-ln/pkg/environment"
 	"github.com/nobe4/gh-ln/pkg/github"
 	"github.com/nobe4/gh-ln/pkg/ln"
 	"github.com/nobe4/gh-ln/pkg/log"
@@ -29,6 +33,12 @@ func main() {
 		o.Level = slog.LevelDebug
 	}
 
+	if e.Command == environment.CommandValidate {
+		validate(e, o)
+
+		return
+	}
+
 	slog.SetDefault(slog.New(handler.New(os.Stdout, o)))
 
 	log.Info("Environment", "parsed", e)
@@ -38,6 +48,12 @@ func main() {
 		c = noop.New()
 	}
 
+	var cached *etag.Client
+	if e.CacheDir != "" {
+		cached = etag.New(c, e.CacheDir)
+		c = cached
+	}
+
 	g := github.New(c, e.Endpoint)
 
 	if err = g.Auth(ctx,
@@ -50,8 +66,30 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := ln.Run(ctx, e, g); err != nil {
+	err = ln.Run(ctx, e, g)
+
+	if cached != nil {
+		hits, misses := cached.Stats()
+		log.Debug("HTTP cache stats", "hits", hits, "misses", misses)
+	}
+
+	if err != nil {
 		log.Error("Running gh-ln failed", "err", err)
 		os.Exit(1)
 	}
 }
+
+// validate only outputs the problems found, to be usable as a pre-commit hook.
+func validate(e environment.Environment, o log.Options) {
+	var w io.Writer = io.Discard
+	if e.Debug {
+		w = os.Stderr
+	}
+
+	slog.SetDefault(slog.New(handler.New(w, o)))
+
+	if err := ln.Validate(os.Stdout, e); err != nil {
+		fmt.Fprintln(os.Stderr, err) //nolint:errcheck // Exiting anyway.
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Package block implements managed blocks: regions of a file, delimited by marker
+lines, whose content is owned by gh-ln. The rest of the file is left untouched.
+
+E.g. with the id `shared`:
+
+	repo-specific content
+	# BEGIN gh-ln:shared
+	managed content
+	# END gh-ln:shared
+	more repo-specific content
+*/
+package block
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Position string
+
+const (
+	PositionStart Position = "start"
+	PositionEnd   Position = "end"
+
+	DefaultComment = "#"
+)
+
+var (
+	ErrInvalidBlock = errors.New("invalid block")
+	ErrUnterminated = errors.New("unterminated block")
+)
+
+// parts are the pieces of a file around a block, the marker lines are part of
+// none of them.
+type parts struct {
+	before, after string
+	found         bool
+}
+
+type Block struct {
+	// ID identifies the block in the file, so multiple blocks can coexist.
+	ID string `json:"id" yaml:"id"`
+
+	// Comment starts the marker lines, and CommentEnd ends them, e.g. `<!--`
+	// and `-->` for Markdown.
+	Comment    string `json:"comment"     yaml:"comment"`
+	CommentEnd string `json:"comment_end" yaml:"comment_end"`
+
+	// Position is where the block is inserted if it is missing.
+	Position Position `json:"position" yaml:"position"`
+}
+
+// Validate checks the block and sets its default values.
+func (b *Block) Validate() error {
+	if b.Comment == "" {
+		b.Comment = DefaultComment
+	}
+
+	switch b.Position {
+	case "":
+		b.Position = PositionEnd
+
+	case PositionStart, PositionEnd:
+
+	default:
+		return fmt.Errorf("%w: unknown position %q, want %q or %q",
+			ErrInvalidBlock, b.Position, PositionStart, PositionEnd)
+	}
+
+	return nil
+}
+
+func (b Block) Begin() string {
+	return b.marker("BEGIN")
+}
+
+func (b Block) End() string {
+	return b.marker("END")
+}
+
+func (b Block) marker(kind string) string {
+	m := fmt.Sprintf("%s %s gh-ln:%s", b.Comment, kind, b.ID)
+
+	if b.CommentEnd != "" {
+		m += " " + b.CommentEnd
+	}
+
+	return m
+}
+
+// Apply returns dest with the block's content set to content. The block is
+// inserted at its position if it is missing.
+func (b Block) Apply(dest, content string) (string, error) {
+	p, err := b.split(dest)
+	if err != nil {
+		return "", err
+	}
+
+	block := b.Begin() + "\n" + withNewline(content) + b.End() + "\n"
+
+	if p.found {
+		return p.before + block + p.after, nil
+	}
+
+	if b.Position == PositionStart {
+		return block + dest, nil
+	}
+
+	return withNewline(dest) + block, nil
+}
+
+// Remove returns dest without the block.
+func (b Block) Remove(dest string) (string, error) {
+	p, err := b.split(dest)
+	if err != nil || !p.found {
+		return dest, err
+	}
+
+	return p.before + p.after, nil
+}
+
+func (b Block) split(s string) (parts, error) {
+	begin, end := b.Begin(), b.End()
+	pieces := [3]strings.Builder{} // The inside is left empty.
+	part := 0
+
+	for line := range strings.Lines(s) {
+		switch trimmed := strings.TrimSpace(line); {
+		case part == 0 && trimmed == begin:
+			part = 1
+
+		case part == 1 && trimmed == end:
+			part = 2
+
+		case part != 1:
+			pieces[part].WriteString(line)
+		}
+	}
+
+	switch part {
+	case 0:
+		return parts{before: s}, nil
+
+	case 1:
+		return parts{}, fmt.Errorf("%w: missing %q", ErrUnterminated, end)
+
+	default:
+		return parts{
+			before: pieces[0].String(),
+			after:  pieces[2].String(),
+			found:  true,
+		}, nil
+	}
+}
+
+func withNewline(s string) string {
+	if s == "" || strings.HasSuffix(s, "\n") {
+		return s
+	}
+
+	return s + "\n"
+}
@@ -0,0 +1,146 @@
+package block
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	b := Block{}
+	if err := b.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if b.Comment != DefaultComment || b.Position != PositionEnd {
+		t.Fatalf("expected default values, got %+v", b)
+	}
+
+	b = Block{Position: "middle"}
+	if err := b.Validate(); !errors.Is(err, ErrInvalidBlock) {
+		t.Fatalf("expected error %v, got %v", ErrInvalidBlock, err)
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	t.Parallel()
+
+	b := Block{ID: "id", Comment: "<!--", CommentEnd: "-->"}
+
+	if want := "<!-- BEGIN gh-ln:id -->"; b.Begin() != want {
+		t.Fatalf("want %q, got %q", want, b.Begin())
+	}
+
+	if want := "<!-- END gh-ln:id -->"; b.End() != want {
+		t.Fatalf("want %q, got %q", want, b.End())
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	end := Block{ID: "id", Comment: "#", Position: PositionEnd}
+	start := Block{ID: "id", Comment: "#", Position: PositionStart}
+
+	tests := []struct {
+		name    string
+		b       Block
+		dest    string
+		content string
+		want    string
+	}{
+		{
+			name:    "empty file",
+			b:       end,
+			content: "a",
+			want:    "# BEGIN gh-ln:id\na\n# END gh-ln:id\n",
+		},
+		{
+			name:    "insert at the end",
+			b:       end,
+			dest:    "x\ny",
+			content: "a\n",
+			want:    "x\ny\n# BEGIN gh-ln:id\na\n# END gh-ln:id\n",
+		},
+		{
+			name:    "insert at the start",
+			b:       start,
+			dest:    "x\ny\n",
+			content: "a\n",
+			want:    "# BEGIN gh-ln:id\na\n# END gh-ln:id\nx\ny\n",
+		},
+		{
+			name:    "replace",
+			b:       start,
+			dest:    "x\n# BEGIN gh-ln:id\nold\nold\n  # END gh-ln:id\ny\n",
+			content: "a\nb\n",
+			want:    "x\n# BEGIN gh-ln:id\na\nb\n# END gh-ln:id\ny\n",
+		},
+		{
+			name:    "other block",
+			b:       end,
+			dest:    "# BEGIN gh-ln:other\nold\n# END gh-ln:other\n",
+			content: "a\n",
+			want:    "# BEGIN gh-ln:other\nold\n# END gh-ln:other\n# BEGIN gh-ln:id\na\n# END gh-ln:id\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.b.Apply(test.dest, test.content)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+
+			// Applying twice doesn't change anything.
+			again, err := test.b.Apply(got, test.content)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if again != got {
+				t.Fatalf("want %q, got %q", got, again)
+			}
+		})
+	}
+
+	t.Run("unterminated", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := end.Apply("# BEGIN gh-ln:id\nold\n", "a")
+		if !errors.Is(err, ErrUnterminated) {
+			t.Fatalf("expected error %v, got %v", ErrUnterminated, err)
+		}
+	})
+}
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	b := Block{ID: "id", Comment: "#"}
+
+	got, err := b.Remove("x\n# BEGIN gh-ln:id\na\n# END gh-ln:id\ny\n")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if want := "x\ny\n"; got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+
+	got, err = b.Remove("x\n")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if want := "x\n"; got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
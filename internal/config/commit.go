@@ -0,0 +1,169 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/nobe4/gh-ln/internal/format"
+)
+
+const (
+	commitMsgTemplate = `auto(ln): update {{ .Data.To.Path }}
+
+Source: {{ .Data.From.HTMLURL }}
+`
+	deleteCommitMsgTemplate = `auto(ln): delete {{ .Data.To.Path }}
+
+Source removed: {{ .Data.From }}
+`
+	groupCommitMsgTemplate = `auto(ln): update {{ len .Data }} files
+`
+)
+
+var (
+	errInvalidCommit = errors.New("invalid commit")
+
+	trailerKeyRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+)
+
+// Commit configures the commits made for a link.
+type Commit struct {
+	// Message is a template for the commit message, rendered with the link as
+	// `.Data`. It is used for updates and deletions, `.Data.SourceMissing`
+	// tells them apart.
+	Message string `json:"message,omitempty" yaml:"message"`
+
+	// Trailers are appended to the message, sorted by key. Their values are
+	// templates as well.
+	Trailers map[string]string `json:"trailers,omitempty" yaml:"trailers"`
+}
+
+func (c *Commit) Validate() error {
+	if _, err := template.New("").Parse(c.Message); err != nil {
+		return fmt.Errorf("%w: message: %w", errInvalidCommit, err)
+	}
+
+	for k, v := range c.Trailers {
+		if !trailerKeyRe.MatchString(k) {
+			return fmt.Errorf("%w: trailer %q: want letters, digits and dashes", errInvalidCommit, k)
+		}
+
+		if _, err := template.New("").Parse(v); err != nil {
+			return fmt.Errorf("%w: trailer %q: %w", errInvalidCommit, k, err)
+		}
+	}
+
+	return nil
+}
+
+// merge returns the commit settings with the values of o taking precedence,
+// trailers are merged.
+func (c *Commit) merge(o *Commit) *Commit {
+	if c == nil || o == nil {
+		return cmp.Or(o, c)
+	}
+
+	trailers := maps.Clone(c.Trailers)
+	if trailers == nil {
+		trailers = map[string]string{}
+	}
+
+	maps.Copy(trailers, o.Trailers)
+
+	return &Commit{
+		Message:  cmp.Or(o.Message, c.Message),
+		Trailers: trailers,
+	}
+}
+
+// commitMessage renders the commit message of the link, followed by its
+// trailers.
+func (l *Link) commitMessage(f format.Formatter) (string, error) {
+	msg, trailers, err := l.message(f)
+	if err != nil {
+		return "", err
+	}
+
+	return withTrailers(msg, trailers), nil
+}
+
+// message renders the commit message and the trailers of the link, sorted by
+// key.
+func (l *Link) message(f format.Formatter) (string, []string, error) {
+	tmpl := commitMsgTemplate
+	if l.deletes() {
+		tmpl = deleteCommitMsgTemplate
+	}
+
+	if l.Commit != nil && l.Commit.Message != "" {
+		tmpl = l.Commit.Message
+	}
+
+	msg, err := f.Format(tmpl, l)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to format the commit message: %w", err)
+	}
+
+	if l.Commit == nil {
+		return msg, nil, nil
+	}
+
+	trailers := []string{}
+
+	for _, k := range slices.Sorted(maps.Keys(l.Commit.Trailers)) {
+		v, err := f.Format(l.Commit.Trailers[k], l)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to format the commit trailer %q: %w", k, err)
+		}
+
+		trailers = append(trailers, k+": "+v)
+	}
+
+	return msg, trailers, nil
+}
+
+// commitMessage renders the message of the commit updating all the links. A
+// single link keeps its own message, otherwise the message lists the subject
+// of each link's message, followed by all their trailers.
+func (l *Links) commitMessage(f format.Formatter) (string, error) {
+	if len(*l) == 1 {
+		return (*l)[0].commitMessage(f)
+	}
+
+	header, err := f.Format(groupCommitMsgTemplate, *l)
+	if err != nil {
+		return "", fmt.Errorf("failed to format the commit message: %w", err)
+	}
+
+	lines := []string{strings.TrimRight(header, "\n"), ""}
+	trailers := []string{}
+
+	for _, link := range *l {
+		msg, t, err := link.message(f)
+		if err != nil {
+			return "", err
+		}
+
+		subject, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+		lines = append(lines, "- "+subject)
+		trailers = append(trailers, t...)
+	}
+
+	slices.Sort(trailers)
+
+	return withTrailers(strings.Join(lines, "\n")+"\n", slices.Compact(trailers)), nil
+}
+
+func withTrailers(msg string, trailers []string) string {
+	if len(trailers) == 0 {
+		return msg
+	}
+
+	return strings.TrimRight(msg, "\n") + "\n\n" + strings.Join(trailers, "\n") + "\n"
+}
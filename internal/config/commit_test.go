@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	fmock "github.com/nobe4/gh-ln/internal/format/mock"
+	"github.com/nobe4/gh-ln/pkg/github"
+)
+
+func TestCommitValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		commit Commit
+		ok     bool
+	}{
+		{name: "empty", ok: true},
+		{
+			name: "valid",
+			commit: Commit{
+				Message:  "chore(sync): update {{ .Data.To.Path }}",
+				Trailers: map[string]string{"Signed-off-by": "Bot <bot@example.com>"},
+			},
+			ok: true,
+		},
+		{name: "invalid message", commit: Commit{Message: "{{ .Data"}},
+		{name: "invalid trailer key", commit: Commit{Trailers: map[string]string{"Signed off": "a"}}},
+		{name: "invalid trailer value", commit: Commit{Trailers: map[string]string{"Refs": "{{ end }}"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.commit.Validate()
+			if test.ok && err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if !test.ok && !errors.Is(err, errInvalidCommit) {
+				t.Fatalf("want error %v, got %v", errInvalidCommit, err)
+			}
+		})
+	}
+}
+
+func TestCommitMerge(t *testing.T) {
+	t.Parallel()
+
+	d := &Commit{Message: "default", Trailers: map[string]string{"A": "default", "B": "default"}}
+	l := &Commit{Trailers: map[string]string{"B": "link"}}
+
+	got := d.merge(l)
+	if got.Message != "default" || got.Trailers["A"] != "default" || got.Trailers["B"] != "link" {
+		t.Fatalf("want merged commit, got %+v", got)
+	}
+
+	if d.Trailers["B"] != "default" {
+		t.Fatalf("want the defaults not to change, got %+v", d)
+	}
+
+	if got := (*Commit)(nil).merge(l); got != l {
+		t.Fatalf("want %+v, got %+v", l, got)
+	}
+
+	if got := d.merge(nil); got != d {
+		t.Fatalf("want %+v, got %+v", d, got)
+	}
+}
+
+func TestLinkCommitMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		commit        *Commit
+		sourceMissing bool
+		want          string
+	}{
+		{
+			name: "default",
+			want: commitMsgTemplate,
+		},
+		{
+			name:          "default deletion",
+			sourceMissing: true,
+			want:          deleteCommitMsgTemplate,
+		},
+		{
+			name:   "message",
+			commit: &Commit{Message: "chore(sync): update"},
+			want:   "chore(sync): update",
+		},
+		{
+			name: "trailers",
+			commit: &Commit{
+				Message: "chore(sync): update\n",
+				Trailers: map[string]string{
+					"Signed-off-by": "Bot <bot@example.com>",
+					"Refs":          "TICKET-1",
+				},
+			},
+			want: "chore(sync): update\n\nRefs: TICKET-1\nSigned-off-by: Bot <bot@example.com>\n",
+		},
+		{
+			name:   "trailers with the default message",
+			commit: &Commit{Trailers: map[string]string{"Refs": "TICKET-1"}},
+			want:   "auto(ln): update {{ .Data.To.Path }}\n\nSource: {{ .Data.From.HTMLURL }}\n\nRefs: TICKET-1\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &Link{
+				To:            github.File{Content: "to"},
+				From:          github.File{Content: "from"},
+				Commit:        test.commit,
+				SourceMissing: test.sourceMissing,
+			}
+
+			got, err := l.commitMessage(fmock.New())
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want message %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestLinksCommitMessage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("single link", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Links{{Commit: &Commit{Message: "chore: update a\n\nbody\n"}}}
+
+		got, err := l.commitMessage(fmock.New())
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if want := "chore: update a\n\nbody\n"; got != want {
+			t.Fatalf("want message %q, got %q", want, got)
+		}
+	})
+
+	t.Run("multiple links", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Links{
+			{Commit: &Commit{Message: "chore: update a\n\nbody\n", Trailers: map[string]string{"Refs": "1"}}},
+			{Commit: &Commit{Message: "chore: update b", Trailers: map[string]string{"Refs": "1", "Acked-by": "me"}}},
+		}
+
+		got, err := l.commitMessage(fmock.New())
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		want := "auto(ln): update {{ len .Data }} files\n\n" +
+			"- chore: update a\n" +
+			"- chore: update b\n\n" +
+			"Acked-by: me\n" +
+			"Refs: 1\n"
+		if got != want {
+			t.Fatalf("want message %q, got %q", want, got)
+		}
+	})
+}
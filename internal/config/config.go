@@ -7,6 +7,7 @@ configurations.
 package config
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -15,6 +16,8 @@ import (
 
 	"github.com/goccy/go-yaml"
 
+	"github.com/nobe4/gh-ln/internal/format"
+	"github.com/nobe4/gh-ln/internal/pool"
 	"github.com/nobe4/gh-ln/pkg/github"
 	"github.com/nobe4/gh-ln/pkg/log"
 )
@@ -25,15 +28,31 @@ var (
 	errInvalidDefaults = errors.New("invalid defaults")
 )
 
+// DefaultHeadName is the head branch name used when the group doesn't set one.
+const DefaultHeadName = "auto-action-ln"
+
 type RawConfig struct {
-	Defaults RawDefaults `yaml:"defaults"`
-	Links    []RawLink   `yaml:"links"`
+	Include  []string            `yaml:"include"`
+	Defaults RawDefaults         `yaml:"defaults"`
+	Groups   map[string]RawGroup `yaml:"groups"`
+	Links    []RawLink           `yaml:"links"`
 }
 
 type Config struct {
-	Source   github.File `json:"source"   yaml:"source"`
-	Defaults Defaults    `json:"defaults" yaml:"defaults"`
-	Links    Links       `json:"links"    yaml:"links"`
+	Source   github.File      `json:"source"   yaml:"source"`
+	Defaults Defaults         `json:"defaults" yaml:"defaults"`
+	Groups   map[string]Group `json:"groups"   yaml:"groups"`
+	Links    Links            `json:"links"    yaml:"links"`
+
+	// ReadInclude reads the files listed in `include`.
+	ReadInclude IncludeReader `json:"-" yaml:"-"`
+
+	// Concurrency is the number of links populated at once.
+	Concurrency int `json:"-" yaml:"-"`
+
+	// Format renders the templates that need the whole context, e.g. the
+	// head branch name.
+	Format format.Formatter `json:"-" yaml:"-"`
 }
 
 func New(source github.File, repo github.Repo) *Config {
@@ -49,24 +68,54 @@ func New(source github.File, repo github.Repo) *Config {
 }
 
 func (c *Config) Parse(r io.Reader) error {
-	var err error
+	rawC, err := decode(r)
+	if err != nil {
+		return err
+	}
+
+	source := c.Source.String()
 
+	return c.parseRaw(rawC, &includes{
+		stack: []string{source},
+		seen:  map[string]bool{source: true},
+	})
+}
+
+func decode(r io.Reader) (RawConfig, error) {
 	rawC := RawConfig{}
 
-	if err = yaml.
+	if err := yaml.
 		NewDecoder(r, yaml.Strict()).
 		Decode(&rawC); err != nil {
-		return fmt.Errorf("%w: %w", errInvalidYAML, err)
+		return RawConfig{}, fmt.Errorf("%w: %w", errInvalidYAML, err)
 	}
 
+	return rawC, nil
+}
+
+// parseRaw parses the config, the included files' links coming before the
+// config's own links.
+func (c *Config) parseRaw(rawC RawConfig, inc *includes) error {
 	if err := c.parseDefaults(rawC.Defaults); err != nil {
 		return fmt.Errorf("%w: %w", errInvalidDefaults, err)
 	}
 
-	if c.Links, err = c.parseLinks(rawC.Links); err != nil {
+	if err := c.parseGroups(rawC.Groups); err != nil {
+		return err
+	}
+
+	included, err := c.parseIncludes(rawC.Include, inc)
+	if err != nil {
+		return err
+	}
+
+	links, err := c.parseLinks(rawC.Links)
+	if err != nil {
 		return fmt.Errorf("%w: %w", errInvalidLinks, err)
 	}
 
+	c.Links = append(included, links...)
+
 	return nil
 }
 
@@ -74,19 +123,97 @@ func (c *Config) Populate(ctx context.Context, g github.Getter) error {
 	log.Group("Populate config")
 	defer log.GroupEnd()
 
-	for i, l := range c.Links {
-		err := l.populate(ctx, g)
-		if err != nil {
+	if err := c.Links.Expand(ctx, g, c); err != nil {
+		return fmt.Errorf("failed to expand links: %w", err)
+	}
+
+	if err := c.resolveHeads(); err != nil {
+		return fmt.Errorf("failed to resolve head branches: %w", err)
+	}
+
+	if p, ok := g.(prefetcher); ok {
+		c.prefetch(ctx, g, p)
+	}
+
+	errs := pool.Run(ctx, c.Concurrency, len(c.Links), func(ctx context.Context, i int) error {
+		l := c.Links[i]
+
+		if err := l.populate(ctx, g); err != nil {
 			return fmt.Errorf("failed to populate link %#v: %w", l, err)
 		}
 
-		// TODO: check if this is really necessary.
-		c.Links[i] = l
+		if l.Skip() {
+			log.WarnContext(ctx, "Source is missing, skipping link", "link", l)
+
+			return nil
+		}
+
+		if err := l.prepare(c); err != nil {
+			return fmt.Errorf("failed to prepare link %#v: %w", l, err)
+		}
+
+		return nil
+	})
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	links := Links{}
+
+	for _, l := range c.Links {
+		if !l.Skip() {
+			links = append(links, l)
+		}
 	}
 
+	c.Links = links
+
 	return nil
 }
 
+// resolveHeads renders the head branch name of each group once, and sets it on
+// the group's links.
+func (c *Config) resolveHeads() error {
+	for _, links := range c.Links.Groups() {
+		repo := links[0].To.Repo
+
+		head, err := c.Format.Format(cmp.Or(c.Group(repo).Pull.Branch, DefaultHeadName), links)
+		if err != nil {
+			return fmt.Errorf("failed to create head branch name for %s: %w", repo, err)
+		}
+
+		for _, l := range links {
+			l.Head = head
+		}
+	}
+
+	return nil
+}
+
+// prefetcher reads many files at once, before they are read one by one.
+type prefetcher interface {
+	Prefetch(ctx context.Context, files []github.File)
+}
+
+// prefetch reads the `from` and `to` files of all the links at once. The
+// `from` refs are resolved first, to know which files to read.
+// The errors are ignored, they are reported when populating each link.
+func (c *Config) prefetch(ctx context.Context, g github.Getter, p prefetcher) {
+	pool.Run(ctx, c.Concurrency, len(c.Links), func(ctx context.Context, i int) error {
+		return c.Links[i].populateFromRef(ctx, g)
+	})
+
+	files := []github.File{}
+
+	for _, l := range c.Links {
+		files = append(files, l.From)
+		files = append(files, l.toCandidates()...)
+	}
+
+	p.Prefetch(ctx, files)
+}
+
 func (c *Config) String() string {
 	out, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
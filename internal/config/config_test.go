@@ -1,13 +1,17 @@
 package config
 
 import (
+	"context"
 	"embed"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
 
+	fmock "github.com/nobe4/gh-ln/internal/format/mock"
 	"github.com/nobe4/gh-ln/pkg/github"
+	"github.com/nobe4/gh-ln/pkg/github/cache"
+	gmock "github.com/nobe4/gh-ln/pkg/github/mock"
 )
 
 //go:embed fixtures/*
@@ -104,3 +108,76 @@ func TestGetMapKey(t *testing.T) {
 		t.Errorf("want \"\", but got %v", got)
 	}
 }
+
+type batch func([]*github.File) []error
+
+func (b batch) GetFiles(_ context.Context, files []*github.File) []error { return b(files) }
+
+func TestPopulatePrefetch(t *testing.T) {
+	t.Parallel()
+
+	repo := github.Repo{Owner: github.User{Login: "owner"}, Repo: "repo"}
+
+	g := cache.New(gmock.Getter{
+		TreeHandler: emptyTree,
+		RepoHandler: func(r *github.Repo) error {
+			r.DefaultBranch = "main"
+
+			return nil
+		},
+		CommitHandler: func(github.Repo, string) (github.Commit, error) {
+			return github.Commit{SHA: "commit"}, nil
+		},
+		FileHandler: func(f *github.File) error {
+			t.Fatalf("want %s to be prefetched", f)
+
+			return nil
+		},
+	})
+
+	batches := 0
+	g.Batch = batch(func(files []*github.File) []error {
+		batches++
+
+		errs := make([]error, len(files))
+
+		for i, f := range files {
+			if f.Path == "to" && f.Ref == "sync/repo" {
+				errs[i] = github.ErrMissingFile
+
+				continue
+			}
+
+			f.Content = f.Path + "@" + f.Ref
+		}
+
+		return errs
+	})
+
+	c := New(github.File{}, repo)
+	c.Concurrency = 2
+	c.Format = fmock.New()
+	c.Defaults.Group.Pull.Branch = "sync/repo"
+	c.Links = Links{
+		{From: github.File{Repo: repo, Path: "a"}, To: github.File{Repo: repo, Path: "to", Ref: "main"}},
+		{From: github.File{Repo: repo, Path: "b", Ref: "v1"}, To: github.File{Repo: repo, Path: "to", Ref: "main"}},
+	}
+
+	if err := c.Populate(t.Context(), g); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	if batches != 1 {
+		t.Fatalf("want 1 batch, got %d", batches)
+	}
+
+	for _, l := range c.Links {
+		if want := l.From.Path + "@" + l.From.Ref; l.From.Content != want {
+			t.Fatalf("want from content %q, got %q", want, l.From.Content)
+		}
+
+		if l.To.Content != "to@main" {
+			t.Fatalf("want to content %q, got %q", "to@main", l.To.Content)
+		}
+	}
+}
@@ -5,11 +5,13 @@ import (
 )
 
 type RawDefaults struct {
-	Link RawLink `yaml:"link"`
+	Link     RawLink `yaml:"link"`
+	RawGroup `yaml:",inline"`
 }
 
 type Defaults struct {
-	Link *Link `json:"link" yaml:"link"`
+	Link  *Link `json:"link"  yaml:"link"`
+	Group `yaml:",inline"`
 }
 
 func (d *Defaults) Equal(o *Defaults) bool {
@@ -19,6 +21,20 @@ func (d *Defaults) Equal(o *Defaults) bool {
 func (c *Config) parseDefaults(raw RawDefaults) error {
 	log.Debug("Parse defaults", "raw", raw)
 
+	g, err := parseGroup(raw.RawGroup)
+	if err != nil {
+		return err
+	}
+
+	c.Defaults.Group = g
+
+	// NOTE: The settings don't depend on the files, they are kept even when
+	// the files don't make a link, e.g. without `from` and `to`.
+	settings, err := parseSettings(raw.Link)
+	if err != nil {
+		return err
+	}
+
 	links, err := c.parseLink(raw.Link)
 	if err != nil {
 		return err
@@ -33,5 +49,11 @@ func (c *Config) parseDefaults(raw RawDefaults) error {
 		c.Defaults.Link = links[0]
 	}
 
+	if c.Defaults.Link == nil {
+		c.Defaults.Link = &Link{}
+	}
+
+	c.Defaults.Link.setSettings(settings)
+
 	return nil
 }
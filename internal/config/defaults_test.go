@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/nobe4/gh-ln/pkg/github"
@@ -94,3 +95,57 @@ func TestParseDefault(t *testing.T) {
 		}
 	})
 }
+
+func TestParseDefaultSettings(t *testing.T) {
+	t.Parallel()
+
+	for name, files := range map[string]string{
+		"without files": "",
+		"with a from":   "    from: o/r:a\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := New(github.File{}, github.Repo{Owner: github.User{Login: "o"}, Repo: "r"})
+
+			err := c.Parse(strings.NewReader(`
+defaults:
+  link:
+` + files + `    commit:
+      message: "sync {{ .Data.To.Path }}"
+    on_source_missing: skip
+    transform:
+      - prepend: "> "
+    render: true
+    vars:
+      a: default
+    mode: "100755"
+
+links:
+  - from: b
+    to: c
+`))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if len(c.Links) != 1 {
+				t.Fatalf("expected 1 link, got %v", c.Links)
+			}
+
+			l := c.Links[0]
+
+			if l.Commit == nil || l.Commit.Message != "sync {{ .Data.To.Path }}" {
+				t.Fatalf("expected the default commit, got %#v", l.Commit)
+			}
+
+			if l.OnSourceMissing != SourceMissingSkip || len(l.Transform) != 1 || l.Render == nil || !*l.Render {
+				t.Fatalf("expected the default settings, got %#v", l)
+			}
+
+			if c.Defaults.Link.Vars["a"] != "default" || l.Mode != github.ModeExecutable {
+				t.Fatalf("expected the default vars and mode, got %#v", l)
+			}
+		})
+	}
+}
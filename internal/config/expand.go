@@ -0,0 +1,337 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/nobe4/gh-ln/internal/glob"
+	"github.com/nobe4/gh-ln/pkg/github"
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+var (
+	errEmptyDir = errors.New("directory is empty")
+	errNoMatch  = errors.New("no file matches the pattern")
+	errSameTo   = errors.New("several files match the same `to`")
+)
+
+// Expand replaces each link that targets more than a single file with one link
+// per file, and each link that targets a repository query with one link per
+// repository.
+func (l *Links) Expand(ctx context.Context, g github.Getter, c *Config) error {
+	newL := Links{}
+
+	for _, link := range *l {
+		links, err := link.expand(ctx, g, c)
+		if err != nil {
+			if link.toleratesMissingSource() && (errors.Is(err, errEmptyDir) || errors.Is(err, errNoMatch)) {
+				log.WarnContext(ctx, "No source file found, skipping link", "link", link, "err", err)
+
+				continue
+			}
+
+			return fmt.Errorf("failed to expand link %s: %w", link, err)
+		}
+
+		newL = append(newL, links...)
+	}
+
+	*l = newL
+
+	l.Filter()
+
+	return nil
+}
+
+func (l *Link) expand(ctx context.Context, g github.Getter, c *Config) (Links, error) {
+	links, err := l.expandFrom(ctx, g, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.ToRepos != nil {
+		if links, err = l.expandRepos(ctx, g, c, links); err != nil {
+			return nil, err
+		}
+	}
+
+	if isDir(l.From.Path) && l.OnSourceMissing == SourceMissingDelete {
+		orphans, err := l.orphans(ctx, g, links)
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, orphans...)
+	}
+
+	return links, nil
+}
+
+// expandRepos creates a copy of each link per repository matching the `to`
+// query.
+func (l *Link) expandRepos(ctx context.Context, g github.Getter, c *Config, links Links) (Links, error) {
+	repos, err := l.toRepos(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := Links{}
+
+	for _, link := range links {
+		for _, r := range repos {
+			newL, err := link.withToRepo(r, c)
+			if err != nil {
+				return nil, err
+			}
+
+			expanded = append(expanded, newL)
+		}
+	}
+
+	log.DebugContext(ctx, "Expanded repos", "link", l, "repos", len(repos), "links", len(expanded))
+
+	return expanded, nil
+}
+
+func (l *Link) expandFrom(ctx context.Context, g github.Getter, c *Config) (Links, error) {
+	switch {
+	case isDir(l.From.Path):
+		return l.expandDir(ctx, g)
+
+	case glob.IsPattern(l.From.Path):
+		return l.expandGlob(ctx, g, c)
+
+	default:
+		return Links{l}, nil
+	}
+}
+
+// expandDir creates one link per file found under the `from` directory, the
+// relative paths are kept under the `to` directory.
+func (l *Link) expandDir(ctx context.Context, g github.Getter) (Links, error) {
+	blobs, err := l.fromBlobs(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := strings.TrimPrefix(l.From.Path, "/")
+	links := Links{}
+
+	for _, e := range blobs {
+		rel, found := strings.CutPrefix(e.Path, dir)
+		if !found {
+			continue
+		}
+
+		link := *l
+		link.From.Path = e.Path
+		link.To.Path = path.Join(l.To.Path, rel)
+		link.rawTo = link.To
+
+		links = append(links, &link)
+	}
+
+	if len(links) == 0 {
+		return nil, fmt.Errorf("%w %#v: %w", errMissingFrom, l.From, errEmptyDir)
+	}
+
+	log.DebugContext(ctx, "Expanded directory", "link", l, "links", len(links))
+
+	return links, nil
+}
+
+// orphans creates a link for each file of the `to` directories that has no
+// source anymore, so it gets deleted like a missing source. The files at the
+// root of a repository are never considered, to not delete a whole repository.
+func (l *Link) orphans(ctx context.Context, g github.Getter, links Links) (Links, error) {
+	dir := strings.Trim(l.To.Path, "/")
+	if dir == "" {
+		log.WarnContext(ctx, "Not deleting files at the root of a repository", "link", l)
+
+		return nil, nil
+	}
+
+	synced := map[string]bool{}
+	samples := map[string]*Link{}
+
+	for _, link := range links {
+		repo := link.To.Repo.String()
+		synced[repo+":"+strings.TrimPrefix(link.To.Path, "/")] = true
+
+		if _, ok := samples[repo]; !ok {
+			samples[repo] = link
+		}
+	}
+
+	orphans := Links{}
+
+	for _, repo := range slices.Sorted(maps.Keys(samples)) {
+		sample := samples[repo]
+
+		blobs, err := toBlobs(ctx, g, sample.To)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range blobs {
+			rel, found := strings.CutPrefix(e.Path, dir+"/")
+			if !found || synced[repo+":"+e.Path] {
+				continue
+			}
+
+			link := *sample
+			link.From.Path = path.Join(l.From.Path, rel)
+			link.To.Path = e.Path
+			link.rawTo = link.To
+
+			orphans = append(orphans, &link)
+		}
+	}
+
+	log.DebugContext(ctx, "Found orphans", "link", l, "links", len(orphans))
+
+	return orphans, nil
+}
+
+// expandGlob creates one link per file matching the `from` pattern. The `to` is
+// filled and templated again for each of them, so it can refer to the matched
+// path. It fails if several files end up with the same `to`, e.g. when it's not
+// templated.
+func (l *Link) expandGlob(ctx context.Context, g github.Getter, c *Config) (Links, error) {
+	blobs, err := l.fromBlobs(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := strings.TrimPrefix(l.From.Path, "/")
+	links := Links{}
+	froms := map[string]string{}
+
+	for _, e := range blobs {
+		ok, err := glob.Match(pattern, e.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%w %#v: %w", errInvalidFrom, l.From, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		link := *l
+		link.From.Path = e.Path
+		link.To = l.rawTo
+		link.fillMissing()
+
+		if err := link.applyTemplate(c); err != nil {
+			return nil, err
+		}
+
+		to := link.To.String()
+		if from, ok := froms[to]; ok {
+			return nil, fmt.Errorf("%w %#v: %w: %s and %s", errInvalidTo, l.To, errSameTo, from, e.Path)
+		}
+
+		froms[to] = e.Path
+		links = append(links, &link)
+	}
+
+	if len(links) == 0 {
+		return nil, fmt.Errorf("%w %#v: %w", errMissingFrom, l.From, errNoMatch)
+	}
+
+	log.DebugContext(ctx, "Expanded pattern", "link", l, "links", len(links))
+
+	return links, nil
+}
+
+// toRepos lists the repositories matching the `to` query.
+func (l *Link) toRepos(ctx context.Context, g github.Getter) ([]github.Repo, error) {
+	infos, err := g.ListRepos(ctx, l.To.Repo.Owner.Login)
+	if err != nil {
+		return nil, fmt.Errorf("%w %#v: %w", errInvalidTo, l.To, err)
+	}
+
+	repos := []github.Repo{}
+
+	for _, info := range infos {
+		ok, err := l.ToRepos.Match(info)
+		if err != nil {
+			return nil, fmt.Errorf("%w %#v: %w", errInvalidRepoQuery, l.ToRepos, err)
+		}
+
+		if ok {
+			repos = append(repos, info.Repo())
+		}
+	}
+
+	if len(repos) == 0 {
+		log.WarnContext(ctx, "No repository matches the query", "owner", l.To.Repo.Owner.Login, "query", l.ToRepos)
+	}
+
+	return repos, nil
+}
+
+// withToRepo creates a copy of the link targeting the repository. The `to` is
+// filled and templated again, so it can refer to the repository.
+func (l *Link) withToRepo(r github.Repo, c *Config) (*Link, error) {
+	link := *l
+	link.ToRepos = nil
+	link.To = l.rawTo
+	link.To.Repo = r
+	link.fillMissing()
+
+	if err := link.applyTemplate(c); err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// fromBlobs lists all the files in the `from` repository.
+func (l *Link) fromBlobs(ctx context.Context, g github.Getter) ([]github.TreeEntry, error) {
+	if err := l.populateFromRef(ctx, g); err != nil {
+		return nil, err
+	}
+
+	tree, err := g.GetTree(ctx, l.From.Repo, l.From.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w %#v: %w", errMissingFrom, l.From, err)
+	}
+
+	return tree.Blobs(), nil
+}
+
+// toBlobs lists all the files in the `to` repository, on its ref or its default
+// branch.
+func toBlobs(ctx context.Context, g github.Getter, to github.File) ([]github.TreeEntry, error) {
+	if to.Ref == "" {
+		if err := g.GetRepo(ctx, &to.Repo); err != nil {
+			return nil, fmt.Errorf("%w %#v: %w", errGettingRepo, to, err)
+		}
+
+		to.Ref = to.Repo.DefaultBranch
+	}
+
+	tree, err := g.GetTree(ctx, to.Repo, to.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w %#v: %w", errMissingTo, to, err)
+	}
+
+	return tree.Blobs(), nil
+}
+
+// isDir reports whether the path designates a directory, i.e. it ends with a
+// `/`.
+func isDir(p string) bool {
+	return strings.HasSuffix(p, "/")
+}
+
+// isPattern reports whether the file's path is a pattern.
+func isPattern(f github.File) bool {
+	return glob.IsPattern(f.Path)
+}
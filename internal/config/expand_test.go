@@ -0,0 +1,357 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nobe4/gh-ln/pkg/github"
+	gmock "github.com/nobe4/gh-ln/pkg/github/mock"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	tree := github.Tree{
+		Entries: []github.TreeEntry{
+			{Path: "a", Type: github.TreeEntryTree},
+			{Path: "a/b", Type: github.TreeEntryBlob},
+			{Path: "a/c", Type: github.TreeEntryTree},
+			{Path: "a/c/d", Type: github.TreeEntryBlob},
+			{Path: "ab", Type: github.TreeEntryBlob},
+			{Path: "e", Type: github.TreeEntryBlob},
+		},
+	}
+
+	g := gmock.Getter{
+		RepoHandler: func(r *github.Repo) error {
+			r.DefaultBranch = "main"
+
+			return nil
+		},
+		TreeHandler: func(_ github.Repo, ref string) (github.Tree, error) {
+			if ref != "main" {
+				t.Fatalf("expected ref to be 'main', got %q", ref)
+			}
+
+			return tree, nil
+		},
+	}
+
+	t.Run("keeps single files", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{From: github.File{Path: "a/b"}, To: github.File{Path: "x"}}}
+
+		if err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if want := "/:a/b@ -> /:x@"; len(l) != 1 || l[0].String() != want {
+			t.Fatalf("expected %q, got %v", want, l)
+		}
+	})
+
+	t.Run("expands a directory", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{From: github.File{Path: "a/"}, To: github.File{Path: "x/"}}}
+
+		if err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := []string{
+			"/:a/b@main -> /:x/b@",
+			"/:a/c/d@main -> /:x/c/d@",
+		}
+
+		if len(l) != len(want) {
+			t.Fatalf("expected %d links, got %d: %v", len(want), len(l), l)
+		}
+
+		for i, w := range want {
+			if l[i].String() != w {
+				t.Fatalf("expected link %d to be %q, got %q", i, w, l[i].String())
+			}
+		}
+	})
+
+	t.Run("expands the root directory", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{From: github.File{Path: "/", Ref: "main"}, To: github.File{Path: "x"}}}
+
+		if err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(l) != 4 {
+			t.Fatalf("expected 4 links, got %d: %v", len(l), l)
+		}
+	})
+
+	t.Run("expands a pattern", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{
+			From:  github.File{Path: "**/[bd]"},
+			To:    github.File{Path: "x/{{ .Link.From.Path }}"},
+			rawTo: github.File{Path: "x/{{ .Link.From.Path }}"},
+		}}
+
+		if err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := []string{
+			"/:a/b@main -> /:x/a/b@",
+			"/:a/c/d@main -> /:x/a/c/d@",
+		}
+
+		if len(l) != len(want) {
+			t.Fatalf("expected %d links, got %d: %v", len(want), len(l), l)
+		}
+
+		for i, w := range want {
+			if l[i].String() != w {
+				t.Fatalf("expected link %d to be %q, got %q", i, w, l[i].String())
+			}
+		}
+	})
+
+	t.Run("expands a pattern without to", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{
+			From:  github.File{Path: "a*", Repo: github.Repo{Repo: "r"}},
+			To:    github.File{Path: "a*", Repo: github.Repo{Repo: "r2"}},
+			rawTo: github.File{Repo: github.Repo{Repo: "r2"}},
+		}}
+
+		if err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if want := "/r:ab@main -> /r2:ab@"; len(l) != 1 || l[0].String() != want {
+			t.Fatalf("expected %q, got %v", want, l)
+		}
+	})
+
+	t.Run("fails when several files match the same to", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{
+			From:  github.File{Path: "**/[bd]"},
+			To:    github.File{Path: "x"},
+			rawTo: github.File{Path: "x"},
+		}}
+
+		err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{}))
+		if !errors.Is(err, errSameTo) {
+			t.Fatalf("expected error %v, got %v", errSameTo, err)
+		}
+	})
+
+	t.Run("fails when no file matches", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{From: github.File{Path: "*.txt"}}}
+
+		err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{}))
+		if !errors.Is(err, errNoMatch) {
+			t.Fatalf("expected error %v, got %v", errNoMatch, err)
+		}
+	})
+
+	t.Run("fails on an empty directory", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{From: github.File{Path: "z/"}, To: github.File{Path: "x/"}}}
+
+		err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{}))
+		if !errors.Is(err, errMissingFrom) {
+			t.Fatalf("expected error %v, got %v", errMissingFrom, err)
+		}
+	})
+
+	t.Run("fails to get the tree", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(_ github.Repo, _ string) (github.Tree, error) {
+				return github.Tree{}, errTest
+			},
+		}
+
+		l := Links{{From: github.File{Path: "a/", Ref: "main"}}}
+
+		err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{}))
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected error %v, got %v", errTest, err)
+		}
+	})
+}
+
+func TestExpandOrphans(t *testing.T) {
+	t.Parallel()
+
+	g := gmock.Getter{
+		TreeHandler: func(github.Repo, string) (github.Tree, error) {
+			return github.Tree{Entries: []github.TreeEntry{
+				{Path: "a/b", Type: github.TreeEntryBlob},
+				{Path: "x", Type: github.TreeEntryBlob},
+				{Path: "x/b", Type: github.TreeEntryBlob},
+				{Path: "x/old", Type: github.TreeEntryBlob},
+				{Path: "x/c/old", Type: github.TreeEntryBlob},
+				{Path: "xy", Type: github.TreeEntryBlob},
+			}}, nil
+		},
+	}
+
+	t.Run("deletes the files without source", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{
+			From:            github.File{Path: "a/", Ref: "main"},
+			To:              github.File{Path: "x/", Ref: "main"},
+			OnSourceMissing: SourceMissingDelete,
+		}}
+
+		if err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := []string{
+			"/:a/b@main -> /:x/b@main",
+			"/:a/old@main -> /:x/old@main",
+			"/:a/c/old@main -> /:x/c/old@main",
+		}
+
+		if len(l) != len(want) {
+			t.Fatalf("expected %d links, got %d: %v", len(want), len(l), l)
+		}
+
+		for i, w := range want {
+			if l[i].String() != w {
+				t.Fatalf("expected link %d to be %q, got %q", i, w, l[i].String())
+			}
+		}
+	})
+
+	t.Run("keeps the files with another policy", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{
+			From:            github.File{Path: "a/", Ref: "main"},
+			To:              github.File{Path: "x/", Ref: "main"},
+			OnSourceMissing: SourceMissingSkip,
+		}}
+
+		if err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(l) != 1 {
+			t.Fatalf("expected 1 link, got %d: %v", len(l), l)
+		}
+	})
+
+	t.Run("keeps the files at the root", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{{
+			From:            github.File{Path: "a/", Ref: "main"},
+			To:              github.File{Path: "/", Ref: "main"},
+			OnSourceMissing: SourceMissingDelete,
+		}}
+
+		if err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(l) != 1 {
+			t.Fatalf("expected 1 link, got %d: %v", len(l), l)
+		}
+	})
+}
+
+func TestExpandRepos(t *testing.T) {
+	t.Parallel()
+
+	g := gmock.Getter{
+		ReposHandler: func(owner string) ([]github.RepoInfo, error) {
+			if owner != "acme" {
+				t.Fatalf("expected owner to be 'acme', got %q", owner)
+			}
+
+			return []github.RepoInfo{
+				{Name: "a", Owner: github.User{Login: "acme"}, Topics: []string{"t"}},
+				{Name: "b", Owner: github.User{Login: "acme"}},
+				{Name: "c", Owner: github.User{Login: "acme"}, Topics: []string{"t"}},
+				{Name: "d", Owner: github.User{Login: "acme"}, Topics: []string{"t"}, Archived: true},
+			}, nil
+		},
+	}
+
+	t.Run("expands the matching repos", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		links, err := c.parseLink(RawLink{
+			From: "o/r:p",
+			To: map[string]any{
+				"owner": "acme",
+				"path":  "{{ .Link.To.Repo.Repo }}.txt",
+				"repos": map[string]any{"topic": "t"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if err := links.Expand(t.Context(), g, c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := []string{
+			"o/r:p@ -> acme/a:a.txt@",
+			"o/r:p@ -> acme/c:c.txt@",
+		}
+
+		if len(links) != len(want) {
+			t.Fatalf("expected %d links, got %d: %v", len(want), len(links), links)
+		}
+
+		for i, w := range want {
+			if links[i].String() != w {
+				t.Fatalf("expected link %d to be %q, got %q", i, w, links[i].String())
+			}
+
+			if links[i].ToRepos != nil {
+				t.Fatalf("expected link %d to have no query, got %+v", i, links[i].ToRepos)
+			}
+		}
+	})
+
+	t.Run("fails to list the repos", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			ReposHandler: func(_ string) ([]github.RepoInfo, error) {
+				return nil, errTest
+			},
+		}
+
+		l := Links{{
+			From:    github.File{Path: "p"},
+			To:      github.File{Repo: github.Repo{Owner: github.User{Login: "acme"}}},
+			ToRepos: &RepoQuery{},
+		}}
+
+		err := l.Expand(t.Context(), g, New(github.File{}, github.Repo{}))
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected error %v, got %v", errTest, err)
+		}
+	})
+}
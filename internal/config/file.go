@@ -9,6 +9,10 @@ import (
 	"github.com/nobe4/gh-ln/pkg/github"
 )
 
+// refPattern matches a git reference, e.g. `main` or `v1.2.3`, as well as a
+// version range, e.g. `^1.4` or `>=1.2,<2`.
+const refPattern = `[\w.,/^~<>=*-]+`
+
 var ErrInvalidFileType = errors.New("invalid file type")
 
 func (c *Config) parseFile(rawFile any) ([]github.File, error) {
@@ -68,7 +72,7 @@ func (*Config) parseMap(rawFile map[string]any) ([]github.File, error) {
 func (*Config) parseString(s string) ([]github.File, error) {
 	// 'https://github.com/owner/repo/blob/ref/path/to/file'
 	if m := regexp.
-		MustCompile(`^https://github.com/(?P<owner>[\w-]+)/(?P<repo>[\w-]+)/blob/(?P<ref>[\w-]+)/(?P<path>.+)$`).
+		MustCompile(`^https://github.com/(?P<owner>[\w-]+)/(?P<repo>[\w-]+)/blob/(?P<ref>[\w.-]+)/(?P<path>.+)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
@@ -84,7 +88,7 @@ func (*Config) parseString(s string) ([]github.File, error) {
 
 	// 'owner/repo/blob/ref/path/to/file'
 	if m := regexp.
-		MustCompile(`^(?P<owner>[\w-]+)/(?P<repo>[\w-]+)/blob/(?P<ref>[\w-]+)/(?P<path>.+)$`).
+		MustCompile(`^(?P<owner>[\w-]+)/(?P<repo>[\w-]+)/blob/(?P<ref>[\w.-]+)/(?P<path>.+)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
@@ -100,7 +104,7 @@ func (*Config) parseString(s string) ([]github.File, error) {
 
 	// 'owner/repo:path/to/file@ref'
 	if m := regexp.
-		MustCompile(`^(?P<owner>[\w-]*)/(?P<repo>[\w-]*):(?P<path>[^@]+)@(?P<ref>[\w-]+)$`).
+		MustCompile(`^(?P<owner>[\w-]*)/(?P<repo>[\w-]*):(?P<path>[^@]+)@(?P<ref>` + refPattern + `)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
@@ -131,7 +135,7 @@ func (*Config) parseString(s string) ([]github.File, error) {
 
 	// 'owner/repo:@ref'
 	if m := regexp.
-		MustCompile(`^(?P<owner>[\w-]*)/(?P<repo>[\w-]*):@(?P<ref>[\w-]+)$`).
+		MustCompile(`^(?P<owner>[\w-]*)/(?P<repo>[\w-]*):@(?P<ref>` + refPattern + `)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
@@ -160,7 +164,7 @@ func (*Config) parseString(s string) ([]github.File, error) {
 
 	// 'path/to/file@ref'
 	if m := regexp.
-		MustCompile(`^(?P<path>[^@]+)@(?P<ref>[\w-]+)$`).
+		MustCompile(`^(?P<path>[^@]+)@(?P<ref>` + refPattern + `)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
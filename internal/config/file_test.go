@@ -309,6 +309,44 @@ func TestParseFile(t *testing.T) {
 			want:  []github.File{{Path: complexPath, Ref: "ref"}},
 		},
 
+		{
+			input: "owner/repo:path@v1.2.3",
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "owner"},
+						Repo:  "repo",
+					},
+					Path: "path",
+					Ref:  "v1.2.3",
+				},
+			},
+		},
+
+		{
+			input: "owner/repo:ci.yml@^1.4",
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "owner"},
+						Repo:  "repo",
+					},
+					Path: "ci.yml",
+					Ref:  "^1.4",
+				},
+			},
+		},
+
+		{
+			input: "path@>=1.2,<2",
+			want:  []github.File{{Path: "path", Ref: ">=1.2,<2"}},
+		},
+
+		{
+			input: "path@latest-release",
+			want:  []github.File{{Path: "path", Ref: "latest-release"}},
+		},
+
 		{
 			input: "path",
 			want:  []github.File{{Path: "path"}},
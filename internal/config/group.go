@@ -0,0 +1,125 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/nobe4/gh-ln/pkg/environment"
+	"github.com/nobe4/gh-ln/pkg/github"
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+var (
+	errInvalidGroups = errors.New("invalid groups")
+	errInvalidMode   = errors.New("invalid mode")
+)
+
+// Mode is how the links of a group are written to the destination repository.
+type Mode string
+
+const (
+	// ModePull commits to a head branch and opens a pull request, it is the
+	// default.
+	ModePull Mode = "pull"
+	// ModeCommit commits directly to the target branch, if it's allowed.
+	ModeCommit Mode = "commit"
+)
+
+// RawGroup holds the settings shared by all the links to a destination
+// repository. It is used in `defaults` and in `groups`.
+type RawGroup struct {
+	Vars   map[string]any `yaml:"vars"`
+	Pull   Pull           `yaml:"pull"`
+	Mode   string         `yaml:"mode"`
+	Branch string         `yaml:"branch"`
+}
+
+type Group struct {
+	Vars map[string]any `json:"vars" yaml:"vars"`
+	Pull Pull           `json:"pull" yaml:"pull"`
+	Mode Mode           `json:"mode" yaml:"mode"`
+
+	// Branch is the branch to commit to with ModeCommit, it defaults to the
+	// default branch.
+	Branch string `json:"branch" yaml:"branch"`
+}
+
+// Group returns the settings for the destination repository, the group's
+// values taking precedence over the defaults.
+func (c *Config) Group(r github.Repo) Group {
+	g := Group{}.merge(c.Defaults.Group)
+
+	if rg, ok := c.Groups[r.String()]; ok {
+		g = g.merge(rg)
+	}
+
+	return g
+}
+
+// merge returns a copy of the group with the values of o taking precedence.
+func (g Group) merge(o Group) Group {
+	vars := maps.Clone(g.Vars)
+	if vars == nil {
+		vars = map[string]any{}
+	}
+
+	maps.Copy(vars, o.Vars)
+
+	return Group{
+		Vars:   vars,
+		Pull:   g.Pull.merge(o.Pull),
+		Mode:   cmp.Or(o.Mode, g.Mode),
+		Branch: cmp.Or(o.Branch, g.Branch),
+	}
+}
+
+func (c *Config) parseGroups(raw map[string]RawGroup) error {
+	c.Groups = map[string]Group{}
+
+	for name, rg := range raw {
+		log.Debug("Parse group", "name", name, "raw", rg)
+
+		if _, err := environment.ParseRepo(name); err != nil {
+			return fmt.Errorf("%w %q: %w", errInvalidGroups, name, err)
+		}
+
+		g, err := parseGroup(rg)
+		if err != nil {
+			return fmt.Errorf("%w %q: %w", errInvalidGroups, name, err)
+		}
+
+		c.Groups[name] = g
+	}
+
+	return nil
+}
+
+func parseGroup(raw RawGroup) (Group, error) {
+	if err := raw.Pull.validate(); err != nil {
+		return Group{}, err
+	}
+
+	mode, err := parseMode(raw.Mode)
+	if err != nil {
+		return Group{}, err
+	}
+
+	return Group{
+		Vars:   raw.Vars,
+		Pull:   raw.Pull,
+		Mode:   mode,
+		Branch: raw.Branch,
+	}, nil
+}
+
+func parseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case "", ModePull, ModeCommit:
+		return m, nil
+
+	default:
+		return "", fmt.Errorf("%w %q: want %q or %q", errInvalidMode, s, ModePull, ModeCommit)
+	}
+}
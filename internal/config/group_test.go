@@ -0,0 +1,163 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nobe4/gh-ln/pkg/github"
+)
+
+func TestParseGroups(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails with an invalid repo", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		err := c.parseGroups(map[string]RawGroup{"repo": {}})
+		if !errors.Is(err, errInvalidGroups) {
+			t.Fatalf("expected error %v, got %v", errInvalidGroups, err)
+		}
+	})
+
+	t.Run("parses groups and defaults", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		err := c.Parse(strings.NewReader(`
+defaults:
+  vars:
+    a: default
+    b: default
+
+groups:
+  o/r:
+    vars:
+      b: group
+`))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		got := c.Group(github.Repo{Owner: github.User{Login: "o"}, Repo: "r"})
+		if got.Vars["a"] != "default" || got.Vars["b"] != "group" {
+			t.Fatalf("expected merged vars, got %v", got.Vars)
+		}
+
+		got = c.Group(github.Repo{Owner: github.User{Login: "o"}, Repo: "other"})
+		if got.Vars["a"] != "default" || got.Vars["b"] != "default" {
+			t.Fatalf("expected default vars, got %v", got.Vars)
+		}
+
+		if c.Defaults.Vars["b"] != "default" {
+			t.Fatalf("expected the defaults not to change, got %v", c.Defaults.Vars)
+		}
+	})
+
+	t.Run("merges pull settings", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		err := c.Parse(strings.NewReader(`
+defaults:
+  pull:
+    title: default
+    labels: [sync]
+    reviewers: [someone]
+    comment: "{{ len .Data }} more files updated"
+
+groups:
+  o/r:
+    pull:
+      labels: [sync, team]
+      reviewers: []
+      draft: true
+`))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		got := c.Group(github.Repo{Owner: github.User{Login: "o"}, Repo: "r"}).Pull
+		if got.Title != "default" ||
+			got.Comment != "{{ len .Data }} more files updated" ||
+			!slices.Equal(got.Labels, []string{"sync", "team"}) ||
+			len(got.Reviewers) != 0 ||
+			!got.IsDraft() {
+			t.Fatalf("expected merged pull settings, got %+v", got)
+		}
+
+		got = c.Group(github.Repo{Owner: github.User{Login: "o"}, Repo: "other"}).Pull
+		if got.Title != "default" ||
+			!slices.Equal(got.Labels, []string{"sync"}) ||
+			!slices.Equal(got.Reviewers, []string{"someone"}) ||
+			got.IsDraft() {
+			t.Fatalf("expected default pull settings, got %+v", got)
+		}
+	})
+
+	t.Run("fails with an invalid milestone", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		err := c.parseGroups(map[string]RawGroup{"o/r": {Pull: Pull{Milestone: -1}}})
+		if !errors.Is(err, errInvalidPull) {
+			t.Fatalf("expected error %v, got %v", errInvalidPull, err)
+		}
+	})
+
+	t.Run("fails with an invalid template", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		err := c.parseGroups(map[string]RawGroup{"o/r": {Pull: Pull{Branch: "sync/{{ .Data"}}})
+		if !errors.Is(err, errInvalidPull) {
+			t.Fatalf("expected error %v, got %v", errInvalidPull, err)
+		}
+	})
+
+	t.Run("merges mode and branch", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		err := c.Parse(strings.NewReader(`
+defaults:
+  branch: docs
+
+groups:
+  o/r:
+    mode: commit
+`))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		got := c.Group(github.Repo{Owner: github.User{Login: "o"}, Repo: "r"})
+		if got.Mode != ModeCommit || got.Branch != "docs" {
+			t.Fatalf("expected commit mode to docs, got %q to %q", got.Mode, got.Branch)
+		}
+
+		got = c.Group(github.Repo{Owner: github.User{Login: "o"}, Repo: "other"})
+		if got.Mode != "" {
+			t.Fatalf("expected the default mode, got %q", got.Mode)
+		}
+	})
+
+	t.Run("fails with an invalid mode", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		err := c.parseGroups(map[string]RawGroup{"o/r": {Mode: "push"}})
+		if !errors.Is(err, errInvalidMode) {
+			t.Fatalf("expected error %v, got %v", errInvalidMode, err)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/nobe4/gh-ln/pkg/github"
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+var (
+	errInvalidInclude = errors.New("invalid include")
+	errIncludeCycle   = errors.New("include cycle")
+	errNoReader       = errors.New("no reader for included files")
+)
+
+// IncludeReader reads an included config file. The file's repo, path and ref
+// are set, and the content is expected in return.
+type IncludeReader func(f github.File) (github.File, error)
+
+// includes tracks the included files while parsing.
+type includes struct {
+	// stack holds the files being included, from the top-level config, to
+	// detect cycles.
+	stack []string
+
+	// seen holds all the included files, so a file included from multiple
+	// places is only parsed once.
+	seen map[string]bool
+}
+
+// parseIncludes parses the included config files and returns their links.
+//
+// An included file inherits the defaults of the file including it, and can
+// override them for its own links. Its groups and variables are merged with the
+// including file's, which take precedence.
+func (c *Config) parseIncludes(raw []string, inc *includes) (Links, error) {
+	links := Links{}
+
+	for _, s := range raw {
+		f, err := c.parseInclude(s)
+		if err != nil {
+			return nil, err
+		}
+
+		key := f.String()
+
+		if slices.Contains(inc.stack, key) {
+			return nil, fmt.Errorf("%w: %s -> %s", errIncludeCycle, strings.Join(inc.stack, " -> "), key)
+		}
+
+		if inc.seen[key] {
+			log.Debug("File is already included, skipping", "file", key)
+
+			continue
+		}
+
+		inc.seen[key] = true
+		inc.stack = append(inc.stack, key)
+
+		l, err := c.include(f, inc)
+
+		inc.stack = inc.stack[:len(inc.stack)-1]
+
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", errInvalidInclude, key, err)
+		}
+
+		links = append(links, l...)
+	}
+
+	return links, nil
+}
+
+// parseInclude finds the included file. A path without repository is relative
+// to the including file.
+func (c *Config) parseInclude(s string) (github.File, error) {
+	files, err := c.parseString(s)
+	if err != nil || len(files) != 1 || files[0].Path == "" {
+		return github.File{}, fmt.Errorf("%w %q: want 'path' or 'owner/repo:path@ref'", errInvalidInclude, s)
+	}
+
+	f := files[0]
+
+	if f.Repo.Empty() {
+		if !path.IsAbs(f.Path) {
+			f.Path = path.Join(path.Dir(c.Source.Path), f.Path)
+		}
+
+		if f.Ref == "" {
+			f.Ref = c.Source.Ref
+		}
+	}
+
+	if f.Repo.Owner.Login == "" {
+		f.Repo.Owner = c.Source.Repo.Owner
+	}
+
+	if f.Repo.Repo == "" {
+		f.Repo.Repo = c.Source.Repo.Repo
+	}
+
+	return f, nil
+}
+
+func (c *Config) include(f github.File, inc *includes) (Links, error) {
+	log.Group("Include " + f.String())
+	defer log.GroupEnd()
+
+	if c.ReadInclude == nil {
+		return nil, errNoReader
+	}
+
+	f, err := c.ReadInclude(f)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := decode(strings.NewReader(f.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Config{
+		Source:      f,
+		Defaults:    c.Defaults,
+		ReadInclude: c.ReadInclude,
+	}
+
+	if err := sub.parseRaw(raw, inc); err != nil {
+		return nil, err
+	}
+
+	c.Defaults.Group = sub.Defaults.Group.merge(c.Defaults.Group)
+
+	for name, g := range sub.Groups {
+		if cg, ok := c.Groups[name]; ok {
+			g = g.merge(cg)
+		}
+
+		c.Groups[name] = g
+	}
+
+	return sub.Links, nil
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nobe4/gh-ln/pkg/github"
+)
+
+func TestParseIncludes(t *testing.T) {
+	t.Parallel()
+
+	repo := github.Repo{Owner: github.User{Login: "owner"}, Repo: "repo"}
+	source := github.File{Repo: repo, Path: "config/.ln-config.yaml", Ref: "main"}
+
+	reader := func(files map[string]string) IncludeReader {
+		return func(f github.File) (github.File, error) {
+			content, ok := files[f.String()]
+			if !ok {
+				return github.File{}, github.ErrMissingFile
+			}
+
+			f.Content = content
+
+			return f, nil
+		}
+	}
+
+	t.Run("merges the included files", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(source, repo)
+		c.ReadInclude = reader(map[string]string{
+			"owner/repo:config/teams/a.yaml@main": `
+include:
+  - b.yaml
+defaults:
+  link:
+    from:
+      owner: owner
+      repo: team-a
+  vars:
+    team: a
+    lang: go
+groups:
+  owner/x:
+    vars:
+      team: a
+links:
+  - from: a.txt
+`,
+			"owner/repo:config/teams/b.yaml@main": `
+links:
+  - from: b.txt
+`,
+			"other/shared:c.yaml@v1": `
+links:
+  - from: other/shared:c.txt
+`,
+		})
+
+		err := c.Parse(strings.NewReader(`
+include:
+  - teams/a.yaml
+  - other/shared:c.yaml@v1
+  - teams/b.yaml
+defaults:
+  vars:
+    team: top
+groups:
+  owner/x:
+    vars:
+      team: top
+links:
+  - from: d.txt
+    to: e.txt
+`))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := []string{
+			"owner/team-a:b.txt@ -> owner/repo:b.txt@",
+			"owner/team-a:a.txt@ -> owner/repo:a.txt@",
+			"other/shared:c.txt@ -> owner/repo:c.txt@",
+			"owner/repo:d.txt@ -> owner/repo:e.txt@",
+		}
+
+		if len(c.Links) != len(want) {
+			t.Fatalf("expected %d links, got %d: %v", len(want), len(c.Links), c.Links)
+		}
+
+		for i, w := range want {
+			if got := c.Links[i].String(); got != w {
+				t.Fatalf("expected link %d to be %q, got %q", i, w, got)
+			}
+		}
+
+		vars := c.Group(github.Repo{Owner: github.User{Login: "owner"}, Repo: "x"}).Vars
+		if vars["team"] != "top" || vars["lang"] != "go" {
+			t.Fatalf("expected the including file's vars to take precedence, got %v", vars)
+		}
+	})
+
+	t.Run("detects cycles", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(source, repo)
+		c.ReadInclude = reader(map[string]string{
+			"owner/repo:config/a.yaml@main": "include: [b.yaml]",
+			"owner/repo:config/b.yaml@main": "include: [.ln-config.yaml]",
+		})
+
+		err := c.Parse(strings.NewReader("include: [a.yaml]"))
+		if !errors.Is(err, errIncludeCycle) {
+			t.Fatalf("expected error %v, got %v", errIncludeCycle, err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		config string
+		reader IncludeReader
+		want   error
+	}{
+		{
+			name:   "without reader",
+			config: "include: [a.yaml]",
+			want:   errNoReader,
+		},
+		{
+			name:   "missing file",
+			config: "include: [a.yaml]",
+			reader: reader(map[string]string{}),
+			want:   github.ErrMissingFile,
+		},
+		{
+			name:   "invalid file",
+			config: "include: [a.yaml]",
+			reader: reader(map[string]string{"owner/repo:config/a.yaml@main": "unknown: true"}),
+			want:   errInvalidYAML,
+		},
+		{
+			name:   "invalid include",
+			config: "include: ['']",
+			reader: reader(map[string]string{}),
+			want:   errInvalidInclude,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := New(source, repo)
+			c.ReadInclude = test.reader
+
+			err := c.Parse(strings.NewReader(test.config))
+			if !errors.Is(err, test.want) {
+				t.Fatalf("expected error %v, got %v", test.want, err)
+			}
+		})
+	}
+}
@@ -1,23 +1,27 @@
 package config
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 
-	"github.com/nobe4/gh-ln/internal/format"
+	"github.com/nobe4/gh-ln/internal/block"
+	"github.com/nobe4/gh-ln/internal/merge"
+	"github.com/nobe4/gh-ln/internal/semver"
 	"github.com/nobe4/gh-ln/internal/template"
+	"github.com/nobe4/gh-ln/internal/transform"
 	"github.com/nobe4/gh-ln/pkg/github"
 	"github.com/nobe4/gh-ln/pkg/log"
 )
 
 const (
-	commitMsgTemplate = `auto(ln): update {{ .Data.To.Path }}
-
-Source: {{ .Data.From.HTMLURL }}
-`
 	linkStringPartCount = 2
+
+	// RefLatestRelease designates the tag of the latest release.
+	RefLatestRelease = "latest-release"
 )
 
 var (
@@ -28,13 +32,65 @@ var (
 	errInvalidTo         = errors.New("to is invalid")
 	errInvalidLinkFormat = errors.New("link format invalid, want 'from -> to'")
 	errFailTemplate      = errors.New("failed to apply template")
+	errInvalidPolicy     = errors.New("invalid policy")
+	errFailTransform     = errors.New("failed to transform")
+	errFailRender        = errors.New("failed to render")
+	errInvalidBlock      = errors.New("invalid block")
+	errInvalidMerge      = errors.New("invalid merge")
+	errResolveRef        = errors.New("failed to resolve ref")
+	errNoMatchingTag     = errors.New("no tag matches the range")
+	errInvalidFileMode   = errors.New("invalid file mode")
 )
 
 type Link struct {
 	From github.File `json:"from" yaml:"from"`
 	To   github.File `json:"to"   yaml:"to"`
 
+	// RequestedRef is the `from` ref as written in the config, when it was
+	// resolved to a tag, e.g. `^1.4` or `latest-release`.
+	RequestedRef string `json:"requested_ref,omitempty" yaml:"requested_ref,omitempty"`
+
+	// OnSourceMissing decides what happens when the `from` file doesn't
+	// exist.
+	OnSourceMissing SourceMissingPolicy `json:"on_source_missing" yaml:"on_source_missing"`
+	SourceMissing   bool                `json:"source_missing"    yaml:"source_missing"`
+
+	// Transform is applied to the `from` content before it is compared and
+	// written to the `to` file.
+	Transform transform.Transforms `json:"transform,omitempty" yaml:"transform,omitempty"`
+
+	// Render executes the `from` content as a template, with Vars merged over
+	// the group's vars.
+	Render *bool          `json:"render,omitempty" yaml:"render,omitempty"`
+	Vars   map[string]any `json:"vars,omitempty"   yaml:"vars,omitempty"`
+
+	// Block restricts the synchronization to a managed block in the `to` file.
+	Block *block.Block `json:"block,omitempty" yaml:"block,omitempty"`
+
+	// Merge merges the `from` document into the `to` one, instead of
+	// replacing it.
+	Merge *merge.Merge `json:"merge,omitempty" yaml:"merge,omitempty"`
+
+	// Commit configures the commit message.
+	Commit *Commit `json:"commit,omitempty" yaml:"commit,omitempty"`
+
+	// Mode overrides the mode of the `to` file, which is the `from` file's
+	// mode by default.
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+
+	// ToRepos selects the `to` repositories, one link is created per matching
+	// repository during the expansion.
+	ToRepos *RepoQuery `json:"to_repos,omitempty" yaml:"to_repos,omitempty"`
+
 	Status Status `json:"status" yaml:"status"`
+
+	// Head is the group's head branch, where the pull request's changes are
+	// pushed.
+	Head string `json:"head,omitempty" yaml:"head,omitempty"`
+
+	// rawTo is the `to` as it was before being filled and templated. It is
+	// used to build the links created during the expansion.
+	rawTo github.File
 }
 
 type Status string
@@ -44,12 +100,34 @@ const (
 	StatusFailedToUpdate  Status = "failed to update"
 	StatusUpdateNotNeeded Status = "update not needed"
 	StatusUpdated         Status = "updated"
+	StatusPending         Status = "already updated in the pull request"
+	StatusDeleted         Status = "deleted"
+	StatusBinaryTransform Status = "not updated: binary files can't be transformed"
+)
+
+type SourceMissingPolicy string
+
+const (
+	// SourceMissingFail fails the whole run, it is the default.
+	SourceMissingFail SourceMissingPolicy = "fail"
+	// SourceMissingSkip ignores the link.
+	SourceMissingSkip SourceMissingPolicy = "skip"
+	// SourceMissingDelete deletes the `to` file.
+	SourceMissingDelete SourceMissingPolicy = "delete"
 )
 
 // The parsing can be done from a couple of various format, see ParseFile.
 type RawLink struct {
-	From any `yaml:"from"`
-	To   any `yaml:"to"`
+	From            any              `yaml:"from"`
+	To              any              `yaml:"to"`
+	OnSourceMissing string           `yaml:"on_source_missing"`
+	Transform       []map[string]any `yaml:"transform"`
+	Render          *bool            `yaml:"render"`
+	Vars            map[string]any   `yaml:"vars"`
+	Block           *block.Block     `yaml:"block"`
+	Merge           *merge.Merge     `yaml:"merge"`
+	Commit          *Commit          `yaml:"commit"`
+	Mode            string           `yaml:"mode"`
 }
 
 func (l *Link) String() string {
@@ -61,60 +139,201 @@ func (l *Link) Equal(other *Link) bool {
 }
 
 func (l *Link) NeedUpdate(ctx context.Context, g github.Getter, head github.Branch) (bool, error) {
-	if l.From.Content == l.To.Content {
-		log.Debug("Content is the same", "from", l.From, "to", l.To)
+	if l.deletes() {
+		return l.needDelete(ctx, g, head)
+	}
+
+	want, err := l.content(l.To.Content)
+	if err != nil {
+		return false, err
+	}
+
+	if l.sameContent(want, l.To) && l.sameMode(l.To) {
+		log.DebugContext(ctx, "Content is the same", "from", l.From, "to", l.To)
 
 		return false, nil
 	}
 
-	headTo := &github.File{
+	return l.differsOn(ctx, g, head)
+}
+
+// differsOn reports whether the `to` file on the branch differs from what it
+// should be.
+func (l *Link) differsOn(ctx context.Context, g github.Getter, b github.Branch) (bool, error) {
+	to := &github.File{
 		Repo: l.To.Repo,
 		Path: l.To.Path,
-		Ref:  head.Name,
+		Ref:  b.Name,
 	}
 
-	log.Debug("Checking head content", "from", l.From, "to@head", headTo)
+	log.DebugContext(ctx, "Checking branch content", "from", l.From, "to@branch", to)
 
-	err := g.GetFile(ctx, headTo)
+	err := g.GetFile(ctx, to)
 	if err != nil {
 		if errors.Is(err, github.ErrMissingFile) {
-			log.Warn("File is missing", "to@head", headTo)
+			log.WarnContext(ctx, "File is missing", "to@branch", to)
 
 			return true, nil
 		}
 
-		return false, fmt.Errorf("failed to get to@head %s: %w", headTo, err)
+		return false, fmt.Errorf("failed to get to@branch %s: %w", to, err)
 	}
 
-	if l.From.Content == headTo.Content {
-		log.Debug("Content is the same", "from", l.From, "to@head", headTo)
+	if err := populateMode(ctx, g, to); err != nil {
+		return false, fmt.Errorf("failed to get to@branch %s: %w", to, err)
+	}
+
+	want, err := l.content(to.Content)
+	if err != nil {
+		return false, err
+	}
+
+	if l.sameContent(want, *to) && l.sameMode(*to) {
+		log.DebugContext(ctx, "Content is the same", "from", l.From, "to@branch", to)
 
 		return false, nil
 	}
 
-	log.Debug("Content differs", "from", l.From, "to@head", headTo)
+	log.DebugContext(ctx, "Content differs", "from", l.From, "to@branch", to)
 
 	return true, nil
 }
 
-func (l *Link) Update(ctx context.Context, g github.Updater, f format.Formatter, head github.Branch) error {
-	log.Info("Processing link", "link", l)
+// sameContent reports whether the file already has the wanted content. Binary
+// files are compared by their blob SHA, when it is known.
+func (l *Link) sameContent(want string, f github.File) bool {
+	if l.From.Binary && f.SHA != "" {
+		return github.BlobSHA([]byte(want)) == f.SHA
+	}
+
+	return want == f.Content
+}
+
+// mode returns the mode the `to` file should have.
+func (l *Link) mode() string {
+	return cmp.Or(l.Mode, l.From.Mode, github.ModeFile)
+}
 
-	l.To.Content = l.From.Content
+// sameMode reports whether the file already has the wanted mode. An unknown
+// mode, e.g. in a truncated tree, is considered the same.
+func (l *Link) sameMode(f github.File) bool {
+	return f.Mode == "" || f.Mode == l.mode()
+}
+
+// content returns the content the `to` file should have, given its current
+// content. A binary file is copied as is.
+func (l *Link) content(current string) (string, error) {
+	switch {
+	case l.From.Binary:
+		return l.From.Content, nil
+
+	case l.Block != nil:
+		return l.blockContent(current)
+
+	case l.Merge != nil:
+		return l.mergeContent(current)
+
+	default:
+		return l.From.Content, nil
+	}
+}
+
+func (l *Link) blockContent(current string) (string, error) {
+	b := *l.Block
+	if b.ID == "" {
+		b.ID = l.From.Path
+	}
+
+	var (
+		content string
+		err     error
+	)
+
+	if l.SourceMissing {
+		content, err = b.Remove(current)
+	} else {
+		content, err = b.Apply(current, l.From.Content)
+	}
 
-	msg, err := f.Format(commitMsgTemplate, l)
 	if err != nil {
-		return fmt.Errorf("failed to format the commit message: %w", err)
+		return "", fmt.Errorf("%w %q in %s: %w", errInvalidBlock, b.ID, l.To, err)
+	}
+
+	return content, nil
+}
+
+// mergeContent merges the `from` document into the current one. Nothing is
+// merged if the source is missing.
+func (l *Link) mergeContent(current string) (string, error) {
+	if l.SourceMissing {
+		return current, nil
+	}
+
+	m := *l.Merge
+	if m.Format == "" {
+		m.Format = merge.FormatOf(l.To.Path)
 	}
 
-	newTo, err := g.UpdateFile(ctx, l.To, head.Name, msg)
+	content, err := m.Apply(current, l.From.Content)
 	if err != nil {
-		return fmt.Errorf("failed to update file: %w", err)
+		return "", fmt.Errorf("%w in %s: %w", errInvalidMerge, l.To, err)
 	}
 
-	log.Info("Updated file", "new to", newTo)
+	return content, nil
+}
 
-	return nil
+// transformsBinary reports whether the link would change the content of a
+// binary file, which is not supported.
+func (l *Link) transformsBinary() bool {
+	rendered := l.Render != nil && *l.Render
+
+	return l.From.Binary && (rendered || len(l.Transform) > 0 || l.Block != nil || l.Merge != nil)
+}
+
+// deletes reports whether the `to` file is to be deleted. With a block, only
+// the block is removed from the file, and with a merge, the file is kept.
+func (l *Link) deletes() bool {
+	return l.SourceMissing && l.Block == nil && l.Merge == nil
+}
+
+// needDelete checks if the `to` file still exists on the head branch.
+func (l *Link) needDelete(ctx context.Context, g github.Getter, head github.Branch) (bool, error) {
+	headTo := &github.File{
+		Repo: l.To.Repo,
+		Path: l.To.Path,
+		Ref:  head.Name,
+	}
+
+	log.DebugContext(ctx, "Checking head for deletion", "from", l.From, "to@head", headTo)
+
+	if err := g.GetFile(ctx, headTo); err != nil {
+		if errors.Is(err, github.ErrMissingFile) {
+			log.DebugContext(ctx, "File is already deleted", "to@head", headTo)
+
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get to@head %s: %w", headTo, err)
+	}
+
+	return true, nil
+}
+
+// Change returns the change to commit for the link, and sets the `to` content
+// to its new value.
+func (l *Link) Change() (github.Change, error) {
+	if l.deletes() {
+		return github.Change{Path: l.To.Path, Delete: true}, nil
+	}
+
+	content, err := l.content(l.To.Content)
+	if err != nil {
+		return github.Change{}, err
+	}
+
+	l.To.Content = content
+
+	return github.Change{Path: l.To.Path, Content: content, Mode: l.mode()}, nil
 }
 
 func (c *Config) ParseLinkString(s string) (Link, error) {
@@ -147,43 +366,272 @@ func (l *Link) populate(ctx context.Context, g github.Getter) error {
 		return err
 	}
 
+	if l.Skip() {
+		return nil
+	}
+
 	return l.populateTo(ctx, g)
 }
 
-func (l *Link) populateFrom(ctx context.Context, g github.Getter) error {
-	// NOTE: Technically speaking, having the `Ref` is not needed to get the
-	// content on the default branch. However, there's no way to get it from
-	// `GetFile`, so getting it in advance is nicer for displaying it later.
-	if l.From.Ref == "" {
-		err := g.GetRepo(ctx, &l.From.Repo)
-		if err != nil {
-			return fmt.Errorf("%w %#v: %w", errGettingRepo, l.From, err)
+// prepare renders and transforms the `from` content, so it can be compared to
+// and written as the `to` content. Binary files and symlinks are left
+// untouched.
+func (l *Link) prepare(c *Config) error {
+	if l.SourceMissing || l.From.Binary || l.From.Mode == github.ModeSymlink {
+		return nil
+	}
+
+	if l.Render != nil && *l.Render {
+		if err := l.render(c); err != nil {
+			return err
 		}
+	}
 
-		l.From.Ref = l.From.Repo.DefaultBranch
+	content, err := l.Transform.Apply(l.From.Content)
+	if err != nil {
+		return fmt.Errorf("%w %#v: %w", errFailTransform, l.From, err)
+	}
+
+	l.From.Content = content
+
+	return nil
+}
+
+func (l *Link) render(c *Config) error {
+	vars := map[string]any{}
+	maps.Copy(vars, c.Defaults.Vars)
+
+	if c.Defaults.Link != nil {
+		maps.Copy(vars, c.Defaults.Link.Vars)
+	}
+
+	if g, ok := c.Groups[l.To.Repo.String()]; ok {
+		maps.Copy(vars, g.Vars)
+	}
+
+	maps.Copy(vars, l.Vars)
+
+	data := struct {
+		Config *Config
+		Link   *Link
+		Vars   map[string]any
+	}{
+		Config: c,
+		Link:   l,
+		Vars:   vars,
+	}
+
+	if err := template.Update(&l.From.Content, data); err != nil {
+		return fmt.Errorf("%w %#v: %w", errFailRender, l.From, err)
+	}
+
+	return nil
+}
+
+// Skip reports whether the link should be ignored because its source is
+// missing.
+func (l *Link) Skip() bool {
+	return l.SourceMissing && l.OnSourceMissing == SourceMissingSkip
+}
+
+func (l *Link) populateFrom(ctx context.Context, g github.Getter) error {
+	if err := l.populateFromRef(ctx, g); err != nil {
+		return err
 	}
 
 	err := g.GetFile(ctx, &l.From)
 	if err != nil {
+		if errors.Is(err, github.ErrMissingFile) && l.toleratesMissingSource() {
+			log.WarnContext(ctx, "Source is missing", "from", l.From, "policy", l.OnSourceMissing)
+
+			l.SourceMissing = true
+
+			return nil
+		}
+
 		return fmt.Errorf("%w %#v: %w", errMissingFrom, l.From, err)
 	}
 
+	if err := populateMode(ctx, g, &l.From); err != nil {
+		return fmt.Errorf("%w %#v: %w", errMissingFrom, l.From, err)
+	}
+
+	return l.populateFromCommit(ctx, g)
+}
+
+// populateMode sets the file's mode from its tree. GetFile follows the
+// symlinks, so the content of a symlink, the path it points to, is read from
+// its blob instead.
+// A file without ref is read from the default branch, so its tree is too.
+// The mode stays unknown if the tree can't be read, or if the file is not in
+// it, e.g. when the tree is truncated.
+func populateMode(ctx context.Context, g github.Getter, f *github.File) error {
+	r, ref := f.Repo, f.Ref
+	if ref == "" {
+		if err := g.GetRepo(ctx, &r); err != nil {
+			log.WarnContext(ctx, "Failed to get the default branch, the mode is unknown", "file", f, "err", err)
+
+			return nil
+		}
+
+		ref = r.DefaultBranch
+	}
+
+	tree, err := g.GetTree(ctx, r, ref)
+	if err != nil {
+		log.WarnContext(ctx, "Failed to get the tree, the mode is unknown", "file", f, "err", err)
+
+		return nil
+	}
+
+	p := strings.TrimPrefix(f.Path, "/")
+
+	for _, e := range tree.Entries {
+		if e.Path != p {
+			continue
+		}
+
+		f.Mode = e.Mode
+
+		if e.Mode == github.ModeSymlink {
+			content, err := g.GetBlob(ctx, f.Repo, e.SHA)
+			if err != nil {
+				return err //nolint:wrapcheck // The error is wrapped by the caller.
+			}
+
+			f.Content = string(content)
+			f.SHA = e.SHA
+			f.Binary = false
+		}
+
+		return nil
+	}
+
+	log.DebugContext(ctx, "File is not in the tree, its mode is unknown", "file", f)
+
 	return nil
 }
 
-func (l *Link) populateTo(ctx context.Context, g github.Getter) error {
-	refs := []string{"auto-action-ln", l.To.Ref}
+// populateFromCommit gets the commit of the `from` ref, e.g. to mention it in
+// the commit message. It is only informational, so failing to get it is not an
+// error.
+func (l *Link) populateFromCommit(ctx context.Context, g github.Getter) error {
+	if l.From.Commit != "" {
+		return nil
+	}
+
+	c, err := g.GetCommit(ctx, l.From.Repo, l.From.Ref)
+	if err != nil {
+		log.WarnContext(ctx, "Failed to get the source commit", "from", l.From, "err", err)
+
+		return nil
+	}
+
+	l.From.Commit = c.SHA
+
+	return nil
+}
+
+func (l *Link) toleratesMissingSource() bool {
+	return l.OnSourceMissing == SourceMissingSkip || l.OnSourceMissing == SourceMissingDelete
+}
+
+// NOTE: Technically speaking, having the `Ref` is not needed to get the
+// content on the default branch. However, there's no way to get it from
+// `GetFile`, so getting it in advance is nicer for displaying it later.
+func (l *Link) populateFromRef(ctx context.Context, g github.Getter) error {
+	switch {
+	case l.From.Ref == "":
+		if err := g.GetRepo(ctx, &l.From.Repo); err != nil {
+			return fmt.Errorf("%w %#v: %w", errGettingRepo, l.From, err)
+		}
+
+		l.From.Ref = l.From.Repo.DefaultBranch
+
+	case l.From.Ref == RefLatestRelease || semver.IsRange(l.From.Ref):
+		return l.resolveFromRef(ctx, g)
+	}
+
+	return nil
+}
+
+// resolveFromRef replaces the `from` ref with the tag it designates: the
+// latest release, or the highest tag matching a version range.
+func (l *Link) resolveFromRef(ctx context.Context, g github.Getter) error {
+	requested := l.From.Ref
+
+	if requested == RefLatestRelease {
+		release, err := g.GetLatestRelease(ctx, l.From.Repo)
+		if err != nil {
+			return fmt.Errorf("%w %#v: %w", errResolveRef, l.From, err)
+		}
+
+		l.From.Ref = release.TagName
+	} else {
+		r, err := semver.ParseRange(requested)
+		if err != nil {
+			return fmt.Errorf("%w %#v: %w", errResolveRef, l.From, err)
+		}
+
+		tags, err := g.ListTags(ctx, l.From.Repo)
+		if err != nil {
+			return fmt.Errorf("%w %#v: %w", errResolveRef, l.From, err)
+		}
+
+		names := make([]string, 0, len(tags))
+		for _, t := range tags {
+			names = append(names, t.Name)
+		}
+
+		tag, ok := r.Highest(names)
+		if !ok {
+			return fmt.Errorf("%w %#v: %w", errResolveRef, l.From, errNoMatchingTag)
+		}
+
+		l.From.Ref = tag
+	}
+
+	l.RequestedRef = requested
+
+	log.DebugContext(ctx, "Resolved ref", "from", l.From, "requested", requested)
+
+	return nil
+}
+
+// toCandidates returns the `to` files to read, in order: on the head branch
+// first, when it is known, then on the ref.
+func (l *Link) toCandidates() []github.File {
+	refs := []string{l.To.Ref}
+	if l.Head != "" {
+		refs = []string{l.Head, l.To.Ref}
+	}
+
+	files := make([]github.File, 0, len(refs))
 
 	for _, ref := range refs {
-		l.To.Ref = ref
+		f := l.To
+		f.Ref = ref
+		files = append(files, f)
+	}
+
+	return files
+}
+
+func (l *Link) populateTo(ctx context.Context, g github.Getter) error {
+	for _, to := range l.toCandidates() {
+		l.To = to
 
 		err := g.GetFile(ctx, &l.To)
 		if err == nil {
+			if err := populateMode(ctx, g, &l.To); err != nil {
+				return fmt.Errorf("%w %#v: %w", errMissingTo, l.To, err)
+			}
+
 			return nil
 		}
 
 		if errors.Is(err, github.ErrMissingFile) {
-			log.Debug("file does not exist", "file", l.To, "ref", l.To.Ref)
+			log.DebugContext(ctx, "file does not exist", "file", l.To, "ref", l.To.Ref)
 
 			continue
 		}
@@ -204,6 +652,29 @@ func (l *Link) fillMissing() {
 	}
 }
 
+func (l *Link) keepRawTo() {
+	l.rawTo = l.To
+}
+
+// setSettings sets the settings parsed by parseSettings, keeping the files.
+func (l *Link) setSettings(s *Link) {
+	l.ToRepos = s.ToRepos
+	l.OnSourceMissing = s.OnSourceMissing
+	l.Transform = s.Transform
+	l.Render = s.Render
+	l.Vars = s.Vars
+	l.Block = s.Block
+	l.Merge = s.Merge
+	l.Commit = s.Commit
+	l.Mode = s.Mode
+}
+
+// deletesPattern reports whether the link deletes the files missing from a
+// pattern.
+func deletesPattern(l *Link) bool {
+	return l.OnSourceMissing == SourceMissingDelete && isPattern(l.From)
+}
+
 func (l *Link) fillDefaults(d Defaults) {
 	if d.Link == nil {
 		return
@@ -219,11 +690,43 @@ func (l *Link) fillDefaults(d Defaults) {
 
 	if l.To.Repo.Empty() {
 		l.To.Repo = d.Link.To.Repo
+
+		if l.ToRepos == nil {
+			l.ToRepos = d.Link.ToRepos
+		}
 	}
 
 	if l.To.Path == "" {
 		l.To.Path = d.Link.To.Path
 	}
+
+	if l.OnSourceMissing == "" {
+		l.OnSourceMissing = d.Link.OnSourceMissing
+	}
+
+	if len(l.Transform) == 0 {
+		l.Transform = d.Link.Transform
+	}
+
+	if l.Render == nil {
+		l.Render = d.Link.Render
+	}
+
+	if l.Block == nil {
+		l.Block = d.Link.Block
+	}
+
+	if l.Merge == nil {
+		l.Merge = d.Link.Merge
+	}
+
+	if l.Mode == "" {
+		l.Mode = d.Link.Mode
+	}
+
+	// NOTE: The vars are merged when rendering, the group's vars come between
+	// the defaults' and the link's.
+	l.Commit = d.Link.Commit.merge(l.Commit)
 }
 
 func (l *Link) applyTemplate(c *Config) error {
@@ -261,3 +764,24 @@ func (l *Link) applyTemplate(c *Config) error {
 
 	return nil
 }
+
+func parseSourceMissingPolicy(s string) (SourceMissingPolicy, error) {
+	switch p := SourceMissingPolicy(s); p {
+	case "", SourceMissingFail, SourceMissingSkip, SourceMissingDelete:
+		return p, nil
+
+	default:
+		return "", fmt.Errorf("%w for on_source_missing: %q", errInvalidPolicy, s)
+	}
+}
+
+func parseFileMode(s string) (string, error) {
+	switch s {
+	case "", github.ModeFile, github.ModeExecutable, github.ModeSymlink:
+		return s, nil
+
+	default:
+		return "", fmt.Errorf("%w %q: want %q, %q or %q",
+			errInvalidFileMode, s, github.ModeFile, github.ModeExecutable, github.ModeSymlink)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"testing"
 
-	fmock "github.com/nobe4/gh-ln/internal/format/mock"
+	"github.com/nobe4/gh-ln/internal/block"
+	"github.com/nobe4/gh-ln/internal/merge"
+	"github.com/nobe4/gh-ln/internal/transform"
 	"github.com/nobe4/gh-ln/pkg/github"
 	gmock "github.com/nobe4/gh-ln/pkg/github/mock"
 )
@@ -16,6 +18,18 @@ const (
 
 var errTest = errors.New("test")
 
+// emptyTree is a tree handler for the tests that don't check the file modes.
+func emptyTree(github.Repo, string) (github.Tree, error) {
+	return github.Tree{}, nil
+}
+
+// defaultBranch is a repo handler for the tests that read files without ref.
+func defaultBranch(r *github.Repo) error {
+	r.DefaultBranch = "main"
+
+	return nil
+}
+
 func TestLinkNeedUpdate(t *testing.T) {
 	t.Parallel()
 
@@ -23,9 +37,10 @@ func TestLinkNeedUpdate(t *testing.T) {
 		t.Parallel()
 
 		g := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(_ *github.File) error { return errTest },
 		}
-		head := github.Branch{New: false}
+		head := github.Branch{Name: "head"}
 		l := &Link{
 			From: github.File{Content: content, Ref: "main"},
 			To:   github.File{Content: content},
@@ -45,9 +60,10 @@ func TestLinkNeedUpdate(t *testing.T) {
 		t.Parallel()
 
 		g := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(_ *github.File) error { return github.ErrMissingFile },
 		}
-		head := github.Branch{New: false}
+		head := github.Branch{Name: "head"}
 		l := &Link{
 			From: github.File{Content: content, Ref: "main"},
 			To:   github.File{Content: "content2"},
@@ -70,9 +86,10 @@ func TestLinkNeedUpdate(t *testing.T) {
 		errWant := errors.New("test")
 
 		g := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(_ *github.File) error { return errWant },
 		}
-		head := github.Branch{New: false}
+		head := github.Branch{Name: "head"}
 		l := &Link{
 			From: github.File{Content: content, Ref: "main"},
 			To:   github.File{Content: "content2"},
@@ -88,13 +105,14 @@ func TestLinkNeedUpdate(t *testing.T) {
 		t.Parallel()
 
 		g := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(f *github.File) error {
 				f.Content = content
 
 				return nil
 			},
 		}
-		head := github.Branch{New: false}
+		head := github.Branch{Name: "head"}
 		l := &Link{
 			From: github.File{Content: content, Ref: "main"},
 			To:   github.File{Content: "content2"},
@@ -114,13 +132,14 @@ func TestLinkNeedUpdate(t *testing.T) {
 		t.Parallel()
 
 		g := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(f *github.File) error {
 				f.Content = "content2"
 
 				return nil
 			},
 		}
-		head := github.Branch{New: false}
+		head := github.Branch{Name: "head"}
 		l := &Link{
 			From: github.File{Content: content, Ref: "main"},
 			To:   github.File{Content: "content2"},
@@ -137,6 +156,336 @@ func TestLinkNeedUpdate(t *testing.T) {
 	})
 }
 
+func TestLinkNeedUpdateBlock(t *testing.T) {
+	t.Parallel()
+
+	b := &block.Block{ID: "id", Comment: "#", Position: block.PositionEnd}
+	head := github.Branch{Name: "head"}
+	g := gmock.Getter{
+		TreeHandler: emptyTree,
+		FileHandler: func(f *github.File) error {
+			f.Content = "head\n# BEGIN gh-ln:id\nold\n# END gh-ln:id\n"
+
+			return nil
+		},
+	}
+
+	t.Run("block is the same", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:  github.File{Content: "content\n"},
+			To:    github.File{Content: "other\n# BEGIN gh-ln:id\ncontent\n# END gh-ln:id\nother\n"},
+			Block: b,
+		}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), g, head)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if needUpdate {
+			t.Fatalf("expected false, got %v", needUpdate)
+		}
+	})
+
+	t.Run("block differs", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:  github.File{Content: "content\n"},
+			To:    github.File{Content: "other\n"},
+			Block: b,
+		}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), g, head)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if !needUpdate {
+			t.Fatalf("expected true, got %v", needUpdate)
+		}
+	})
+
+	t.Run("block is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:  github.File{Content: "content\n"},
+			To:    github.File{Content: "# BEGIN gh-ln:id\n"},
+			Block: b,
+		}
+
+		_, err := l.NeedUpdate(t.Context(), g, head)
+		if !errors.Is(err, errInvalidBlock) {
+			t.Fatalf("expected error %v, got %v", errInvalidBlock, err)
+		}
+	})
+}
+
+func TestLinkNeedUpdateBinary(t *testing.T) {
+	t.Parallel()
+
+	const binary = "\x00\x01\xff"
+
+	head := github.Branch{Name: "head"}
+	sha := github.BlobSHA([]byte(binary))
+
+	tests := []struct {
+		name    string
+		to      github.File
+		headSHA string
+		want    bool
+	}{
+		{
+			name: "same blob on base branch",
+			to:   github.File{SHA: sha},
+			want: false,
+		},
+		{
+			name:    "same blob on head branch",
+			to:      github.File{SHA: "old"},
+			headSHA: sha,
+			want:    false,
+		},
+		{
+			name:    "different blob",
+			to:      github.File{SHA: "old"},
+			headSHA: "old",
+			want:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := gmock.Getter{
+				TreeHandler: emptyTree,
+				FileHandler: func(f *github.File) error {
+					// NOTE: The content is ignored, only the SHA is compared.
+					f.Content = binary
+					f.SHA = test.headSHA
+
+					return nil
+				},
+			}
+
+			l := &Link{
+				From: github.File{Content: binary, Binary: true},
+				To:   test.to,
+			}
+
+			got, err := l.NeedUpdate(t.Context(), g, head)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestLinkNeedUpdateMode(t *testing.T) {
+	t.Parallel()
+
+	head := github.Branch{Name: "head"}
+
+	tests := []struct {
+		name     string
+		link     Link
+		headMode string
+		want     bool
+	}{
+		{
+			name: "same mode",
+			link: Link{
+				From: github.File{Content: content, Mode: github.ModeExecutable},
+				To:   github.File{Content: content, Mode: github.ModeExecutable},
+			},
+			want: false,
+		},
+		{
+			name: "unknown mode",
+			link: Link{
+				From: github.File{Content: content, Mode: github.ModeExecutable},
+				To:   github.File{Content: content},
+			},
+			want: false,
+		},
+		{
+			name: "mode differs",
+			link: Link{
+				From: github.File{Content: content, Mode: github.ModeExecutable},
+				To:   github.File{Content: content, Mode: github.ModeFile},
+			},
+			headMode: github.ModeFile,
+			want:     true,
+		},
+		{
+			name: "mode is overridden",
+			link: Link{
+				From: github.File{Content: content, Mode: github.ModeExecutable},
+				To:   github.File{Content: content, Mode: github.ModeExecutable},
+				Mode: github.ModeFile,
+			},
+			headMode: github.ModeExecutable,
+			want:     true,
+		},
+		{
+			name: "mode is updated on head",
+			link: Link{
+				From: github.File{Content: content, Mode: github.ModeExecutable},
+				To:   github.File{Content: content, Mode: github.ModeFile},
+			},
+			headMode: github.ModeExecutable,
+			want:     false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := gmock.Getter{
+				FileHandler: func(f *github.File) error {
+					f.Content = content
+
+					return nil
+				},
+				TreeHandler: func(_ github.Repo, ref string) (github.Tree, error) {
+					if ref != head.Name {
+						t.Fatalf("want tree of %q, got %q", head.Name, ref)
+					}
+
+					return github.Tree{Entries: []github.TreeEntry{{Mode: test.headMode}}}, nil
+				},
+			}
+
+			got, err := test.link.NeedUpdate(t.Context(), g, head)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+
+	t.Run("mode differs on the default branch", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			RepoHandler: defaultBranch,
+			FileHandler: func(f *github.File) error {
+				if f.Ref == head.Name {
+					return github.ErrMissingFile
+				}
+
+				f.Content = content
+
+				return nil
+			},
+			TreeHandler: func(_ github.Repo, ref string) (github.Tree, error) {
+				if ref != "main" {
+					t.Fatalf("want tree of %q, got %q", "main", ref)
+				}
+
+				return github.Tree{Entries: []github.TreeEntry{{Path: "a.sh", Mode: github.ModeFile}}}, nil
+			},
+		}
+
+		l := &Link{
+			From: github.File{Content: content, Mode: github.ModeExecutable},
+			To:   github.File{Path: "a.sh"},
+			Head: head.Name,
+		}
+
+		if err := l.populateTo(t.Context(), g); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if l.To.Mode != github.ModeFile {
+			t.Fatalf("want mode %q, got %q", github.ModeFile, l.To.Mode)
+		}
+
+		got, err := l.NeedUpdate(t.Context(), g, head)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if !got {
+			t.Fatal("want an update")
+		}
+	})
+}
+
+func TestLinkNeedDelete(t *testing.T) {
+	t.Parallel()
+
+	head := github.Branch{Name: "head"}
+
+	t.Run("to is already deleted", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(_ *github.File) error { return github.ErrMissingFile },
+		}
+		l := &Link{SourceMissing: true}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), g, head)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if needUpdate {
+			t.Fatalf("expected false, got %v", needUpdate)
+		}
+	})
+
+	t.Run("can't get the to file", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(_ *github.File) error { return errTest },
+		}
+		l := &Link{SourceMissing: true}
+
+		_, err := l.NeedUpdate(t.Context(), g, head)
+		if !errors.Is(err, errTest) {
+			t.Fatalf("want error %v, got %v", errTest, err)
+		}
+	})
+
+	t.Run("to exists on head", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(f *github.File) error {
+				if f.Ref != head.Name {
+					t.Fatalf("expected ref to be %q, got %q", head.Name, f.Ref)
+				}
+
+				return nil
+			},
+		}
+		l := &Link{SourceMissing: true}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), g, head)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if !needUpdate {
+			t.Fatalf("expected true, got %v", needUpdate)
+		}
+	})
+}
+
 func TestParseLinkString(t *testing.T) {
 	t.Parallel()
 
@@ -202,6 +551,7 @@ func TestPopulate(t *testing.T) {
 		t.Parallel()
 
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
 			RepoHandler: func(_ *github.Repo) error { return errTest },
 		}
 
@@ -217,6 +567,10 @@ func TestPopulate(t *testing.T) {
 		t.Parallel()
 
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
+			CommitHandler: func(_ github.Repo, _ string) (github.Commit, error) {
+				return github.Commit{SHA: "commit"}, nil
+			},
 			FileHandler: func(f *github.File) error {
 				if f.Path == "from" {
 					return nil
@@ -240,6 +594,10 @@ func TestPopulate(t *testing.T) {
 		t.Parallel()
 
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
+			CommitHandler: func(_ github.Repo, _ string) (github.Commit, error) {
+				return github.Commit{SHA: "commit"}, nil
+			},
 			FileHandler: func(f *github.File) error {
 				if f.Path == "from" {
 					f.Content = "got"
@@ -270,6 +628,11 @@ func TestPopulate(t *testing.T) {
 		t.Parallel()
 
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
+			RepoHandler: defaultBranch,
+			CommitHandler: func(_ github.Repo, _ string) (github.Commit, error) {
+				return github.Commit{SHA: "commit"}, nil
+			},
 			FileHandler: func(f *github.File) error {
 				f.Content = "got " + f.Path
 
@@ -297,54 +660,199 @@ func TestPopulate(t *testing.T) {
 	})
 }
 
-func TestPopulateFrom(t *testing.T) {
+func TestPrepare(t *testing.T) {
 	t.Parallel()
 
-	const branch = "branch"
+	yes := true
+	repo := github.Repo{Owner: github.User{Login: "o"}, Repo: "r"}
 
-	t.Run("fails to get the repo", func(t *testing.T) {
+	t.Run("transforms the from content", func(t *testing.T) {
 		t.Parallel()
 
-		f := gmock.Getter{
-			RepoHandler: func(_ *github.Repo) error { return errTest },
+		l := &Link{
+			From:      github.File{Content: "from"},
+			To:        github.File{Content: "to"},
+			Transform: transform.Transforms{transform.Prepend{Text: "> "}},
 		}
 
-		l := &Link{}
+		if err := l.prepare(New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
 
-		err := l.populateFrom(t.Context(), f)
-		if !errors.Is(err, errGettingRepo) {
-			t.Fatalf("expected error %v, got %v", errGettingRepo, err)
+		if l.From.Content != "> from" {
+			t.Fatalf("expected from to be transformed, got %#v", l.From)
+		}
+
+		if l.To.Content != "to" {
+			t.Fatalf("expected to not to be transformed, got %#v", l.To)
 		}
 	})
 
-	t.Run("fails to get the file", func(t *testing.T) {
+	t.Run("fails to transform", func(t *testing.T) {
 		t.Parallel()
 
-		f := gmock.Getter{
-			FileHandler: func(_ *github.File) error { return errTest },
-			RepoHandler: func(r *github.Repo) error {
-				r.DefaultBranch = branch
+		l := &Link{
+			From:      github.File{Content: "from"},
+			Transform: transform.Transforms{transform.Strip{Begin: "from", End: "end"}},
+		}
 
-				return nil
-			},
+		err := l.prepare(New(github.File{}, github.Repo{}))
+		if !errors.Is(err, errFailTransform) {
+			t.Fatalf("expected error %v, got %v", errFailTransform, err)
 		}
+	})
 
-		l := &Link{}
+	t.Run("does not render by default", func(t *testing.T) {
+		t.Parallel()
 
-		err := l.populateFrom(t.Context(), f)
-		if !errors.Is(err, errMissingFrom) {
-			t.Fatalf("expected error %v, got %v", errMissingFrom, err)
+		l := &Link{From: github.File{Content: "{{ .Vars.a }}"}}
+
+		if err := l.prepare(New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if l.From.Content != "{{ .Vars.a }}" {
+			t.Fatalf("expected from not to be rendered, got %#v", l.From.Content)
 		}
 	})
 
-	t.Run("gets the file with default ref", func(t *testing.T) {
+	t.Run("renders with the vars", func(t *testing.T) {
 		t.Parallel()
 
-		f := gmock.Getter{
-			FileHandler: func(f *github.File) error {
-				f.Content = content
+		c := New(github.File{Path: "config"}, github.Repo{})
+		c.Defaults.Vars = map[string]any{"a": "default", "b": "default", "c": "default", "d": "default"}
+		c.Defaults.Link.Vars = map[string]any{"b": "default link", "c": "default link", "d": "default link"}
+		c.Groups = map[string]Group{
+			"o/r":     {Vars: map[string]any{"b": "group", "c": "group"}},
+			"o/other": {Vars: map[string]any{"b": "other", "c": "other"}},
+		}
 
-				return nil
+		l := &Link{
+			From: github.File{
+				Content: "{{ .Vars.a }} {{ .Vars.b }} {{ .Vars.c }} {{ .Vars.d }} {{ .Link.To.Repo }} {{ .Config.Source.Path }}",
+			},
+			To:        github.File{Repo: repo},
+			Render:    &yes,
+			Vars:      map[string]any{"c": "link"},
+			Transform: transform.Transforms{transform.Append{Text: " {{ .Vars.a }}"}},
+		}
+
+		l.fillDefaults(c.Defaults)
+
+		if err := l.prepare(c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if want := "default group link default link o/r config {{ .Vars.a }}"; l.From.Content != want {
+			t.Fatalf("expected %q, got %q", want, l.From.Content)
+		}
+	})
+
+	t.Run("fails to render", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:   github.File{Content: "{{ .Missing }}"},
+			Render: &yes,
+		}
+
+		err := l.prepare(New(github.File{}, github.Repo{}))
+		if !errors.Is(err, errFailRender) {
+			t.Fatalf("expected error %v, got %v", errFailRender, err)
+		}
+	})
+
+	t.Run("skips a missing source", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			SourceMissing: true,
+			Render:        &yes,
+			Transform:     transform.Transforms{transform.Prepend{Text: "> "}},
+		}
+
+		if err := l.prepare(New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if l.From.Content != "" {
+			t.Fatalf("expected from to stay empty, got %#v", l.From.Content)
+		}
+	})
+
+	t.Run("skips a binary source", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:      github.File{Content: "\x00{{ .Vars.a }}", Binary: true},
+			Render:    &yes,
+			Transform: transform.Transforms{transform.Prepend{Text: "> "}},
+		}
+
+		if err := l.prepare(New(github.File{}, github.Repo{})); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if l.From.Content != "\x00{{ .Vars.a }}" {
+			t.Fatalf("expected from to stay the same, got %#v", l.From.Content)
+		}
+	})
+}
+
+func TestPopulateFrom(t *testing.T) {
+	t.Parallel()
+
+	const branch = "branch"
+
+	t.Run("fails to get the repo", func(t *testing.T) {
+		t.Parallel()
+
+		f := gmock.Getter{
+			TreeHandler: emptyTree,
+			RepoHandler: func(_ *github.Repo) error { return errTest },
+		}
+
+		l := &Link{}
+
+		err := l.populateFrom(t.Context(), f)
+		if !errors.Is(err, errGettingRepo) {
+			t.Fatalf("expected error %v, got %v", errGettingRepo, err)
+		}
+	})
+
+	t.Run("fails to get the file", func(t *testing.T) {
+		t.Parallel()
+
+		f := gmock.Getter{
+			TreeHandler: emptyTree,
+			FileHandler: func(_ *github.File) error { return errTest },
+			RepoHandler: func(r *github.Repo) error {
+				r.DefaultBranch = branch
+
+				return nil
+			},
+		}
+
+		l := &Link{}
+
+		err := l.populateFrom(t.Context(), f)
+		if !errors.Is(err, errMissingFrom) {
+			t.Fatalf("expected error %v, got %v", errMissingFrom, err)
+		}
+	})
+
+	t.Run("gets the file with default ref", func(t *testing.T) {
+		t.Parallel()
+
+		f := gmock.Getter{
+			TreeHandler: emptyTree,
+			CommitHandler: func(_ github.Repo, _ string) (github.Commit, error) {
+				return github.Commit{SHA: "commit"}, nil
+			},
+			FileHandler: func(f *github.File) error {
+				f.Content = content
+
+				return nil
 			},
 			RepoHandler: func(r *github.Repo) error {
 				r.DefaultBranch = branch
@@ -373,6 +881,10 @@ func TestPopulateFrom(t *testing.T) {
 		t.Parallel()
 
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
+			CommitHandler: func(_ github.Repo, ref string) (github.Commit, error) {
+				return github.Commit{SHA: "commit@" + ref}, nil
+			},
 			FileHandler: func(f *github.File) error {
 				f.Content = content
 
@@ -401,7 +913,298 @@ func TestPopulateFrom(t *testing.T) {
 		if l.From.Ref != "main" {
 			t.Fatalf("expected ref to stay to 'main', got %#v", l.From.Ref)
 		}
+
+		if l.From.Commit != "commit@main" {
+			t.Fatalf("expected commit to be 'commit@main', got %#v", l.From.Commit)
+		}
+	})
+
+	t.Run("ignores a failure to get the commit", func(t *testing.T) {
+		t.Parallel()
+
+		f := gmock.Getter{
+			TreeHandler: emptyTree,
+			CommitHandler: func(_ github.Repo, _ string) (github.Commit, error) {
+				return github.Commit{}, errors.New("nope") //nolint:err113 // Test error.
+			},
+			FileHandler: func(f *github.File) error {
+				f.Content = content
+
+				return nil
+			},
+		}
+
+		l := &Link{From: github.File{Ref: "main"}}
+
+		if err := l.populateFrom(t.Context(), f); err != nil {
+			t.Fatalf("expected no error got %v", err)
+		}
+
+		if l.From.Commit != "" {
+			t.Fatalf("expected no commit, got %#v", l.From.Commit)
+		}
+	})
+}
+
+func TestPopulateFromResolvesRef(t *testing.T) {
+	t.Parallel()
+
+	g := gmock.Getter{
+		TreeHandler: emptyTree,
+		CommitHandler: func(_ github.Repo, _ string) (github.Commit, error) {
+			return github.Commit{SHA: "commit"}, nil
+		},
+		FileHandler: func(f *github.File) error {
+			f.Content = f.Ref
+
+			return nil
+		},
+		TagsHandler: func(_ github.Repo) ([]github.Tag, error) {
+			return []github.Tag{{Name: "v1.3.0"}, {Name: "v1.4.1"}, {Name: "v1.5.0"}, {Name: "v2.0.0"}}, nil
+		},
+		LatestReleaseHandler: func(_ github.Repo) (github.Release, error) {
+			return github.Release{TagName: "v2.0.0"}, nil
+		},
+	}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "^1.4", want: "v1.5.0"},
+		{ref: "~1.4", want: "v1.4.1"},
+		{ref: "latest-release", want: "v2.0.0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ref, func(t *testing.T) {
+			t.Parallel()
+
+			l := &Link{From: github.File{Ref: test.ref}}
+
+			if err := l.populateFrom(t.Context(), g); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if l.From.Ref != test.want || l.From.Content != test.want {
+				t.Fatalf("expected ref %q, got %q", test.want, l.From.Ref)
+			}
+
+			if l.RequestedRef != test.ref {
+				t.Fatalf("expected requested ref %q, got %q", test.ref, l.RequestedRef)
+			}
+		})
+	}
+
+	t.Run("fails without matching tag", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{From: github.File{Ref: "^3"}}
+
+		err := l.populateFrom(t.Context(), g)
+		if !errors.Is(err, errNoMatchingTag) {
+			t.Fatalf("expected error %v, got %v", errNoMatchingTag, err)
+		}
+	})
+
+	t.Run("fails to get the release", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: emptyTree,
+			LatestReleaseHandler: func(_ github.Repo) (github.Release, error) {
+				return github.Release{}, errTest
+			},
+		}
+
+		l := &Link{From: github.File{Ref: RefLatestRelease}}
+
+		err := l.populateFrom(t.Context(), g)
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected error %v, got %v", errTest, err)
+		}
+	})
+}
+
+func TestPopulateFromMissing(t *testing.T) {
+	t.Parallel()
+
+	g := gmock.Getter{
+		FileHandler: func(_ *github.File) error { return github.ErrMissingFile },
+	}
+
+	t.Run("fails by default", func(t *testing.T) {
+		t.Parallel()
+
+		for _, p := range []SourceMissingPolicy{"", SourceMissingFail} {
+			l := &Link{From: github.File{Ref: "main"}, OnSourceMissing: p}
+
+			err := l.populateFrom(t.Context(), g)
+			if !errors.Is(err, errMissingFrom) {
+				t.Fatalf("expected error %v, got %v", errMissingFrom, err)
+			}
+		}
+	})
+
+	t.Run("tolerates a missing source", func(t *testing.T) {
+		t.Parallel()
+
+		for _, p := range []SourceMissingPolicy{SourceMissingSkip, SourceMissingDelete} {
+			l := &Link{From: github.File{Ref: "main"}, OnSourceMissing: p}
+
+			if err := l.populateFrom(t.Context(), g); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !l.SourceMissing {
+				t.Fatalf("expected the source to be missing for %q", p)
+			}
+
+			if l.Skip() != (p == SourceMissingSkip) {
+				t.Fatalf("expected skip to be %v for %q", p == SourceMissingSkip, p)
+			}
+		}
+	})
+
+	t.Run("fails on other errors", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(_ *github.File) error { return errTest },
+		}
+
+		l := &Link{From: github.File{Ref: "main"}, OnSourceMissing: SourceMissingSkip}
+
+		err := l.populateFrom(t.Context(), g)
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected error %v, got %v", errTest, err)
+		}
+	})
+}
+
+func TestPopulateMode(t *testing.T) {
+	t.Parallel()
+
+	tree := github.Tree{Entries: []github.TreeEntry{
+		{Path: "a.sh", Mode: github.ModeExecutable, SHA: "a"},
+		{Path: "link", Mode: github.ModeSymlink, SHA: "link"},
+	}}
+
+	g := gmock.Getter{
+		RepoHandler: func(r *github.Repo) error {
+			r.DefaultBranch = "main"
+
+			return nil
+		},
+		TreeHandler: func(_ github.Repo, ref string) (github.Tree, error) {
+			if ref != "main" {
+				return github.Tree{}, errTest
+			}
+
+			return tree, nil
+		},
+		BlobHandler: func(_ github.Repo, sha string) ([]byte, error) {
+			if sha != "link" {
+				return nil, errTest
+			}
+
+			return []byte("a.sh"), nil
+		},
+	}
+
+	t.Run("sets the mode", func(t *testing.T) {
+		t.Parallel()
+
+		f := &github.File{Path: "/a.sh", Content: content}
+
+		if err := populateMode(t.Context(), g, f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if f.Mode != github.ModeExecutable || f.Content != content {
+			t.Fatalf("want an executable with its content, got %#v", f)
+		}
+	})
+
+	t.Run("reads the symlink", func(t *testing.T) {
+		t.Parallel()
+
+		// NOTE: GetFile followed the symlink.
+		f := &github.File{Path: "link", Content: content, SHA: "a"}
+
+		if err := populateMode(t.Context(), g, f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if f.Mode != github.ModeSymlink || f.Content != "a.sh" || f.SHA != "link" {
+			t.Fatalf("want a symlink to a.sh, got %#v", f)
+		}
 	})
+
+	t.Run("keeps the mode unknown", func(t *testing.T) {
+		t.Parallel()
+
+		f := &github.File{Path: "missing"}
+
+		if err := populateMode(t.Context(), g, f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if f.Mode != "" {
+			t.Fatalf("want no mode, got %q", f.Mode)
+		}
+	})
+
+	t.Run("fails to get the tree", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) { return github.Tree{}, errTest },
+		}
+
+		f := &github.File{Path: "a.sh", Ref: "main"}
+
+		if err := populateMode(t.Context(), g, f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if f.Mode != "" {
+			t.Fatalf("want no mode, got %q", f.Mode)
+		}
+	})
+}
+
+func TestParseFileMode(t *testing.T) {
+	t.Parallel()
+
+	for _, m := range []string{"", github.ModeFile, github.ModeExecutable, github.ModeSymlink} {
+		if got, err := parseFileMode(m); err != nil || got != m {
+			t.Errorf("want %q, got %q and %v", m, got, err)
+		}
+	}
+
+	if _, err := parseFileMode("755"); !errors.Is(err, errInvalidFileMode) {
+		t.Errorf("expected error %v, got %v", errInvalidFileMode, err)
+	}
+}
+
+func TestParseSourceMissingPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []string{"", "fail", "skip", "delete"} {
+		got, err := parseSourceMissingPolicy(p)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if string(got) != p {
+			t.Fatalf("expected %q, got %q", p, got)
+		}
+	}
+
+	if _, err := parseSourceMissingPolicy("ignore"); !errors.Is(err, errInvalidPolicy) {
+		t.Fatalf("expected error %v, got %v", errInvalidPolicy, err)
+	}
 }
 
 func TestPopulateTo(t *testing.T) {
@@ -411,10 +1214,11 @@ func TestPopulateTo(t *testing.T) {
 		t.Parallel()
 
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(_ *github.File) error { return errTest },
 		}
 
-		l := &Link{}
+		l := &Link{Head: "head", To: github.File{Ref: "main"}}
 
 		err := l.populateTo(t.Context(), f)
 		if !errors.Is(err, errMissingTo) {
@@ -426,6 +1230,7 @@ func TestPopulateTo(t *testing.T) {
 		t.Parallel()
 
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(f *github.File) error {
 				f.Content = gotTo
 
@@ -433,7 +1238,35 @@ func TestPopulateTo(t *testing.T) {
 			},
 		}
 
-		l := &Link{}
+		l := &Link{Head: "head", To: github.File{Ref: "main"}}
+
+		err := l.populateTo(t.Context(), f)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if l.To.Content != gotTo || l.To.Ref != "head" {
+			t.Fatalf("expected to be populated from the head branch, got %#v", l.To)
+		}
+	})
+
+	t.Run("gets the file on ref without head branch", func(t *testing.T) {
+		t.Parallel()
+
+		f := gmock.Getter{
+			TreeHandler: emptyTree,
+			FileHandler: func(f *github.File) error {
+				if f.Ref != "main" {
+					t.Fatalf("expected to only read the ref, got %#v", f)
+				}
+
+				f.Content = gotTo
+
+				return nil
+			},
+		}
+
+		l := &Link{To: github.File{Ref: "main"}}
 
 		err := l.populateTo(t.Context(), f)
 		if err != nil {
@@ -450,6 +1283,7 @@ func TestPopulateTo(t *testing.T) {
 
 		i := 0
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(_ *github.File) error {
 				if i == 0 {
 					i++
@@ -461,7 +1295,7 @@ func TestPopulateTo(t *testing.T) {
 			},
 		}
 
-		l := &Link{}
+		l := &Link{Head: "head", To: github.File{Ref: "main"}}
 
 		err := l.populateTo(t.Context(), f)
 		if !errors.Is(err, errMissingTo) {
@@ -474,6 +1308,7 @@ func TestPopulateTo(t *testing.T) {
 
 		i := 0
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(f *github.File) error {
 				if i == 0 {
 					i++
@@ -487,7 +1322,7 @@ func TestPopulateTo(t *testing.T) {
 			},
 		}
 
-		l := &Link{}
+		l := &Link{Head: "head", To: github.File{Ref: "main"}}
 
 		err := l.populateTo(t.Context(), f)
 		if err != nil {
@@ -503,12 +1338,13 @@ func TestPopulateTo(t *testing.T) {
 		t.Parallel()
 
 		f := gmock.Getter{
+			TreeHandler: emptyTree,
 			FileHandler: func(_ *github.File) error {
 				return github.ErrMissingFile
 			},
 		}
 
-		l := &Link{}
+		l := &Link{Head: "head", To: github.File{Ref: "main"}}
 
 		err := l.populateTo(t.Context(), f)
 		if err != nil {
@@ -521,29 +1357,24 @@ func TestPopulateTo(t *testing.T) {
 	})
 }
 
-func TestLinkUpdate(t *testing.T) {
+func TestLinkChange(t *testing.T) {
 	t.Parallel()
 
-	head := github.Branch{Name: "head"}
-
-	t.Run("fail to update", func(t *testing.T) {
+	t.Run("update", func(t *testing.T) {
 		t.Parallel()
 
-		g := gmock.Updater{
-			Handler: func(_ github.File, _ string, _ string) (github.File, error) {
-				return github.File{}, errTest
-			},
-		}
-
 		l := &Link{
-			To:   github.File{Content: "to"},
+			To:   github.File{Path: "to", Content: "to"},
 			From: github.File{Content: "from"},
 		}
 
-		err := l.Update(t.Context(), g, fmock.New(), head)
+		got, err := l.Change()
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
 
-		if !errors.Is(err, errTest) {
-			t.Fatalf("want error %v, got %v", errTest, err)
+		if want := (github.Change{Path: "to", Content: "from", Mode: github.ModeFile}); got != want {
+			t.Fatalf("want %+v, got %+v", want, got)
 		}
 
 		if l.To.Content != l.From.Content {
@@ -551,28 +1382,122 @@ func TestLinkUpdate(t *testing.T) {
 		}
 	})
 
-	t.Run("update", func(t *testing.T) {
+	t.Run("delete", func(t *testing.T) {
 		t.Parallel()
 
-		g := gmock.Updater{
-			Handler: func(_ github.File, _ string, _ string) (github.File, error) {
-				// NOTE: the returned file is currently not used.
-				return github.File{}, nil
-			},
+		l := &Link{SourceMissing: true, To: github.File{Path: "to", Content: "to"}}
+
+		got, err := l.Change()
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if want := (github.Change{Path: "to", Delete: true}); got != want {
+			t.Fatalf("want %+v, got %+v", want, got)
 		}
+	})
+}
+
+func TestLinkChangeBlock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("updates the block", func(t *testing.T) {
+		t.Parallel()
 
 		l := &Link{
-			To:   github.File{Content: "to"},
-			From: github.File{Content: "from"},
+			From:  github.File{Content: "content", Path: "from"},
+			To:    github.File{Content: "a\n# BEGIN gh-ln:from\nold\n# END gh-ln:from\nb\n"},
+			Block: &block.Block{Comment: "#", Position: block.PositionEnd},
+		}
+
+		if _, err := l.Change(); err != nil {
+			t.Fatalf("want no error, got %v", err)
 		}
 
-		err := l.Update(t.Context(), g, fmock.New(), head)
+		if want := "a\n# BEGIN gh-ln:from\ncontent\n# END gh-ln:from\nb\n"; l.To.Content != want {
+			t.Fatalf("want %q, got %q", want, l.To.Content)
+		}
+	})
+
+	t.Run("removes the block of a missing source", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:          github.File{Path: "from"},
+			To:            github.File{Content: "a\n# BEGIN gh-ln:id\nold\n# END gh-ln:id\nb\n"},
+			Block:         &block.Block{ID: "id", Comment: "#", Position: block.PositionEnd},
+			SourceMissing: true,
+		}
+
+		got, err := l.Change()
 		if err != nil {
 			t.Fatalf("want no error, got %v", err)
 		}
 
-		if l.To.Content != l.From.Content {
-			t.Fatal("want link content to be updated but isn't")
+		if got.Delete {
+			t.Fatal("want the file to be kept")
+		}
+
+		if want := "a\nb\n"; l.To.Content != want {
+			t.Fatalf("want %q, got %q", want, l.To.Content)
+		}
+	})
+}
+
+func TestLinkChangeMerge(t *testing.T) {
+	t.Parallel()
+
+	head := github.Branch{Name: "head"}
+
+	t.Run("merges the documents", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:  github.File{Content: `{"a": {"b": 2}}`},
+			To:    github.File{Path: "to.json", Content: "{\"a\": {\"b\": 1, \"c\": 3}}\n"},
+			Merge: &merge.Merge{Strategy: merge.StrategyDeep},
+		}
+
+		if _, err := l.Change(); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if want := "{\n  \"a\": {\n    \"b\": 2,\n    \"c\": 3\n  }\n}\n"; l.To.Content != want {
+			t.Fatalf("want %q, got %q", want, l.To.Content)
+		}
+	})
+
+	t.Run("keeps the file of a missing source", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			To:            github.File{Path: "to.yml", Content: "a: 1\n"},
+			Merge:         &merge.Merge{Strategy: merge.StrategyDeep},
+			SourceMissing: true,
+		}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), gmock.Getter{}, head)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if needUpdate {
+			t.Fatalf("want no update, got %v", needUpdate)
+		}
+	})
+
+	t.Run("fails to merge", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:  github.File{Content: "a: ["},
+			To:    github.File{Path: "to.yml", Content: "a: 1\n"},
+			Merge: &merge.Merge{Strategy: merge.StrategyDeep},
+		}
+
+		_, err := l.Change()
+		if !errors.Is(err, errInvalidMerge) {
+			t.Fatalf("want error %v, got %v", errInvalidMerge, err)
 		}
 	})
 }
@@ -654,6 +1579,69 @@ func TestParseLink(t *testing.T) {
 	}
 }
 
+func TestParseLinkMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets the defaults", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := New(github.File{}, github.Repo{}).parseLink(RawLink{From: "a.yml", To: "b.yml", Merge: &merge.Merge{}})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 1 || got[0].Merge == nil || got[0].Merge.Strategy != merge.StrategyDeep {
+			t.Fatalf("expected a deep merge, got %+v", got)
+		}
+	})
+
+	tests := []struct {
+		name string
+		rl   RawLink
+	}{
+		{
+			name: "invalid strategy",
+			rl:   RawLink{From: "a.yml", Merge: &merge.Merge{Strategy: "x"}},
+		},
+		{
+			name: "with a block",
+			rl:   RawLink{From: "a.yml", Merge: &merge.Merge{}, Block: &block.Block{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(github.File{}, github.Repo{}).parseLink(test.rl)
+			if !errors.Is(err, errInvalidMerge) {
+				t.Fatalf("expected error %v, got %v", errInvalidMerge, err)
+			}
+		})
+	}
+}
+
+func TestParseLinkDeletePattern(t *testing.T) {
+	t.Parallel()
+
+	c := New(github.File{}, github.Repo{})
+
+	_, err := c.parseLink(RawLink{From: "*.yml", OnSourceMissing: string(SourceMissingDelete)})
+	if !errors.Is(err, errInvalidPolicy) {
+		t.Fatalf("expected error %v, got %v", errInvalidPolicy, err)
+	}
+
+	if _, err := c.parseLink(RawLink{From: "a/", OnSourceMissing: string(SourceMissingDelete)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	c.Defaults.Link.OnSourceMissing = SourceMissingDelete
+
+	if _, err := c.parseLink(RawLink{From: "*.yml"}); !errors.Is(err, errInvalidPolicy) {
+		t.Fatalf("expected error %v with the default policy, got %v", errInvalidPolicy, err)
+	}
+}
+
 func TestFillMissing(t *testing.T) {
 	t.Parallel()
 
@@ -2,10 +2,14 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/nobe4/gh-ln/internal/format"
+	"github.com/nobe4/gh-ln/internal/transform"
 	"github.com/nobe4/gh-ln/pkg/github"
+	"github.com/nobe4/gh-ln/pkg/github/cache"
 	"github.com/nobe4/gh-ln/pkg/log"
 )
 
@@ -56,9 +60,26 @@ func (c *Config) parseLink(raw RawLink) (Links, error) {
 		return nil, fmt.Errorf("%w: %w", errInvalidTo, err)
 	}
 
+	settings, err := parseSettings(raw)
+	if err != nil {
+		return nil, err
+	}
+
 	links := combineLinks(froms, tos)
 
+	for _, l := range links {
+		l.setSettings(settings)
+	}
+
 	links.FillDefaults(c.Defaults)
+
+	// NOTE: The `to` of a pattern is a template, so the files to delete can't
+	// be known.
+	if slices.ContainsFunc(links, deletesPattern) {
+		return nil, fmt.Errorf("%w: %q doesn't apply to patterns", errInvalidPolicy, SourceMissingDelete)
+	}
+
+	links.KeepRawTo()
 	links.FillMissing()
 
 	if err := links.ApplyTemplate(c); err != nil {
@@ -70,6 +91,64 @@ func (c *Config) parseLink(raw RawLink) (Links, error) {
 	return links, nil
 }
 
+// parseSettings parses the settings of a link, i.e. everything but its `from`
+// and `to` files. They are returned on a link without files.
+func parseSettings(raw RawLink) (*Link, error) {
+	query, err := parseRepoQuery(raw.To)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidTo, err)
+	}
+
+	policy, err := parseSourceMissingPolicy(raw.OnSourceMissing)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := parseFileMode(raw.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	transforms, err := transform.Parse(raw.Transform)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // The error is descriptive enough.
+	}
+
+	if raw.Block != nil {
+		if err := raw.Block.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidBlock, err)
+		}
+	}
+
+	if raw.Merge != nil {
+		if raw.Block != nil {
+			return nil, fmt.Errorf("%w: cannot be combined with a block", errInvalidMerge)
+		}
+
+		if err := raw.Merge.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidMerge, err)
+		}
+	}
+
+	if raw.Commit != nil {
+		if err := raw.Commit.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Link{
+		ToRepos:         query,
+		OnSourceMissing: policy,
+		Transform:       transforms,
+		Render:          raw.Render,
+		Vars:            raw.Vars,
+		Block:           raw.Block,
+		Merge:           raw.Merge,
+		Commit:          raw.Commit,
+		Mode:            mode,
+	}, nil
+}
+
 func combineLinks(froms, tos []github.File) Links {
 	if len(froms) == 0 {
 		return combineLinksNoFrom(tos)
@@ -123,6 +202,12 @@ func (l *Links) FillMissing() {
 	}
 }
 
+func (l *Links) KeepRawTo() {
+	for _, l := range *l {
+		l.keepRawTo()
+	}
+}
+
 func (l *Links) FillDefaults(d Defaults) {
 	for _, l := range *l {
 		l.fillDefaults(d)
@@ -156,42 +241,180 @@ func (l *Links) Filter() {
 	*l = newL
 }
 
+// Update commits the links that need it to the head branch, in a single
+// commit. Either all of them are updated, or none.
 func (l *Links) Update(
 	ctx context.Context,
 	g github.GetterUpdater,
 	f format.Formatter,
 	head github.Branch,
 ) bool {
-	updated := false
+	changed := Links{}
+	changes := []github.Change{}
+
+	// NOTE: The branch doesn't change until the commit, so its trees are read
+	// once for all the links.
+	r := cache.New(g)
 
 	for _, link := range *l {
-		needUpdate, err := link.NeedUpdate(ctx, g, head)
+		if link.transformsBinary() {
+			log.WarnContext(ctx, "Binary files can't be transformed", "link", link)
+			link.Status = StatusBinaryTransform
+
+			continue
+		}
+
+		needUpdate, err := link.NeedUpdate(ctx, r, head)
 		if err != nil {
-			log.Error("failed to check if link needs update", "link", link, "error", err)
+			log.ErrorContext(ctx, "failed to check if link needs update", "link", link, "error", err)
 			link.Status = StatusFailedToCheck
 
 			continue
 		}
 
 		if !needUpdate {
-			log.Info("Update not needed", "link", link)
+			log.InfoContext(ctx, "Update not needed", "link", link)
 			link.Status = StatusUpdateNotNeeded
 
 			continue
 		}
 
-		if err := link.Update(ctx, g, f, head); err != nil {
-			log.Error("failed to update", "link", link, "error", err)
+		log.InfoContext(ctx, "Processing link", "link", link)
+
+		change, err := link.Change()
+		if err != nil {
+			log.ErrorContext(ctx, "failed to update", "link", link, "error", err)
 			link.Status = StatusFailedToUpdate
 
 			continue
 		}
 
-		updated = true
+		changed = append(changed, link)
+		changes = append(changes, change)
+	}
+
+	if len(changed) == 0 {
+		return false
+	}
+
+	if err := changed.commit(ctx, g, f, head, changes); err != nil {
+		log.ErrorContext(ctx, "failed to commit", "branch", head.Name, "error", err)
+
+		for _, link := range changed {
+			link.Status = StatusFailedToUpdate
+		}
+
+		return false
+	}
+
+	for _, link := range changed {
 		link.Status = StatusUpdated
+
+		if link.SourceMissing {
+			link.Status = StatusDeleted
+		}
 	}
 
-	return updated
+	return true
+}
+
+// PopulateToOn reads the `to` files on the branch, instead of the head branch
+// or the ref read by Populate, e.g. to commit to the branch directly. The files
+// missing from the branch are left empty.
+func (l *Links) PopulateToOn(ctx context.Context, g github.Getter, b github.Branch) error {
+	r := cache.New(g)
+
+	for _, link := range *l {
+		to := github.File{Repo: link.To.Repo, Path: link.To.Path, Ref: b.Name}
+
+		err := r.GetFile(ctx, &to)
+
+		switch {
+		case errors.Is(err, github.ErrMissingFile):
+			log.DebugContext(ctx, "file does not exist", "file", to)
+
+			to = github.File{Repo: link.To.Repo, Path: link.To.Path, Ref: b.Name}
+
+		case err != nil:
+			return fmt.Errorf("%w %#v: %w", errMissingTo, to, err)
+
+		default:
+			if err := populateMode(ctx, r, &to); err != nil {
+				return fmt.Errorf("%w %#v: %w", errMissingTo, to, err)
+			}
+		}
+
+		link.To = to
+	}
+
+	return nil
+}
+
+// NeedUpdateOn reports whether any link still needs an update on the branch,
+// regardless of the `to` files. E.g. a pull request is not needed anymore when
+// its base branch is already up to date.
+// A link that failed is considered as needing an update. A link not needed on
+// the head branch but needed on the branch is marked as StatusPending, its
+// update is already in the pull request.
+func (l *Links) NeedUpdateOn(ctx context.Context, g github.Getter, b github.Branch) (bool, error) {
+	r := cache.New(g)
+	needed := false
+
+	for _, link := range *l {
+		if link.Status == StatusFailedToCheck || link.Status == StatusFailedToUpdate {
+			needed = true
+
+			continue
+		}
+
+		if link.transformsBinary() {
+			continue
+		}
+
+		check := link.differsOn
+		if link.deletes() {
+			check = link.needDelete
+		}
+
+		need, err := check(ctx, r, b)
+		if err != nil {
+			return false, err
+		}
+
+		if !need {
+			continue
+		}
+
+		needed = true
+
+		if link.Status == StatusUpdateNotNeeded {
+			link.Status = StatusPending
+		}
+	}
+
+	return needed, nil
+}
+
+func (l *Links) commit(
+	ctx context.Context,
+	g github.Updater,
+	f format.Formatter,
+	head github.Branch,
+	changes []github.Change,
+) error {
+	msg, err := l.commitMessage(f)
+	if err != nil {
+		return err
+	}
+
+	commit, err := g.CommitChanges(ctx, (*l)[0].To.Repo, head, msg, changes)
+	if err != nil {
+		return fmt.Errorf("failed to commit the changes: %w", err)
+	}
+
+	log.InfoContext(ctx, "Committed changes", "branch", head.Name, "commit", commit.SHA, "files", len(changes))
+
+	return nil
 }
 
 type Groups map[string]Links
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
+	"slices"
 	"testing"
 
 	fmock "github.com/nobe4/gh-ln/internal/format/mock"
+	"github.com/nobe4/gh-ln/internal/transform"
 	"github.com/nobe4/gh-ln/pkg/github"
 	gmock "github.com/nobe4/gh-ln/pkg/github/mock"
 )
@@ -177,7 +180,7 @@ func TestFilter(t *testing.T) {
 func TestLinksUpdate(t *testing.T) {
 	t.Parallel()
 
-	head := github.Branch{New: false}
+	head := github.Branch{Name: "head"}
 
 	const got = "got"
 
@@ -185,6 +188,7 @@ func TestLinksUpdate(t *testing.T) {
 		t.Parallel()
 
 		g := gmock.GetterUpdater{
+			GetTreeHandler: emptyTree,
 			GetFileHandler: func(*github.File) error { return errTest },
 		}
 
@@ -206,6 +210,40 @@ func TestLinksUpdate(t *testing.T) {
 		}
 	})
 
+	yes := true
+
+	for name, link := range map[string]*Link{
+		"transform": {Transform: transform.Transforms{transform.Prepend{Text: "> "}}},
+		"render":    {Render: &yes},
+	} {
+		t.Run("do not "+name+" a binary link", func(t *testing.T) {
+			t.Parallel()
+
+			g := gmock.GetterUpdater{
+				GetTreeHandler: emptyTree,
+				CommitHandler: func(github.Repo, github.Branch, string, []github.Change) (github.Commit, error) {
+					t.Fatal("want no commit")
+
+					return github.Commit{}, nil
+				},
+			}
+
+			link.From = github.File{Content: "\x00from", Binary: true}
+			link.To = github.File{Content: "to"}
+			l := &Links{link}
+
+			updated := l.Update(t.Context(), g, fmock.New(), head)
+
+			if s := (*l)[0].Status; s != StatusBinaryTransform {
+				t.Fatalf("want status %q, got %q", StatusBinaryTransform, s)
+			}
+
+			if updated {
+				t.Fatal("want to not be updated")
+			}
+		})
+	}
+
 	t.Run("do not update the link", func(t *testing.T) {
 		t.Parallel()
 
@@ -224,17 +262,49 @@ func TestLinksUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("reads the branch tree once", func(t *testing.T) {
+		t.Parallel()
+
+		trees := 0
+		g := gmock.GetterUpdater{
+			GetTreeHandler: func(github.Repo, string) (github.Tree, error) {
+				trees++
+
+				return github.Tree{}, nil
+			},
+			GetFileHandler: func(f *github.File) error {
+				f.Content = "from"
+
+				return nil
+			},
+		}
+
+		l := &Links{
+			{From: github.File{Path: "a", Content: "from"}, To: github.File{Path: "a", Content: "to"}},
+			{From: github.File{Path: "b", Content: "from"}, To: github.File{Path: "b", Content: "to"}},
+		}
+
+		if l.Update(t.Context(), g, fmock.New(), head) {
+			t.Fatal("want to not be updated")
+		}
+
+		if trees != 1 {
+			t.Fatalf("want 1 tree read, got %d", trees)
+		}
+	})
+
 	t.Run("fail to update the link", func(t *testing.T) {
 		t.Parallel()
 
 		g := gmock.GetterUpdater{
+			GetTreeHandler: emptyTree,
 			GetFileHandler: func(f *github.File) error {
 				f.Content = got
 
 				return nil
 			},
-			UpdateHandler: func(github.File, string, string) (github.File, error) {
-				return github.File{}, errTest
+			CommitHandler: func(github.Repo, github.Branch, string, []github.Change) (github.Commit, error) {
+				return github.Commit{}, errTest
 			},
 		}
 
@@ -260,13 +330,14 @@ func TestLinksUpdate(t *testing.T) {
 		t.Parallel()
 
 		g := gmock.GetterUpdater{
+			GetTreeHandler: emptyTree,
 			GetFileHandler: func(f *github.File) error {
 				f.Content = got
 
 				return nil
 			},
-			UpdateHandler: func(github.File, string, string) (github.File, error) {
-				return github.File{}, nil
+			CommitHandler: func(github.Repo, github.Branch, string, []github.Change) (github.Commit, error) {
+				return github.Commit{}, nil
 			},
 		}
 
@@ -287,18 +358,19 @@ func TestLinksUpdate(t *testing.T) {
 	t.Run("multiple links", func(t *testing.T) {
 		t.Parallel()
 
+		var gotChanges []github.Change
+
 		g := gmock.GetterUpdater{
+			GetTreeHandler: emptyTree,
 			GetFileHandler: func(f *github.File) error {
 				f.Content = got
 
 				return nil
 			},
-			UpdateHandler: func(f github.File, _ string, _ string) (github.File, error) {
-				if f.Content == "error" {
-					return github.File{}, errTest
-				}
+			CommitHandler: func(_ github.Repo, _ github.Branch, _ string, c []github.Change) (github.Commit, error) {
+				gotChanges = c
 
-				return github.File{}, nil
+				return github.Commit{}, nil
 			},
 		}
 
@@ -312,13 +384,13 @@ func TestLinksUpdate(t *testing.T) {
 			// Updates correctly
 			{
 				From: github.File{Content: "from"},
-				To:   github.File{Content: "to"},
+				To:   github.File{Path: "a", Content: "to"},
 			},
 
-			// Fails to update
+			// Deletes correctly
 			{
-				From: github.File{Content: "error"},
-				To:   github.File{Content: "to"},
+				To:            github.File{Path: "b", Content: "to"},
+				SourceMissing: true,
 			},
 		}
 
@@ -332,15 +404,228 @@ func TestLinksUpdate(t *testing.T) {
 			t.Fatalf("want status 'updated', got '%s'", s)
 		}
 
-		if s := (*l)[2].Status; s != "failed to update" {
-			t.Fatalf("want status 'failed to update', got '%s'", s)
+		if s := (*l)[2].Status; s != "deleted" {
+			t.Fatalf("want status 'deleted', got '%s'", s)
+		}
+
+		want := []github.Change{
+			{Path: "a", Content: "from", Mode: github.ModeFile},
+			{Path: "b", Delete: true},
+		}
+		if !slices.Equal(want, gotChanges) {
+			t.Fatalf("want changes %+v, got %+v", want, gotChanges)
 		}
 
-		// The function failed but we had a valid update.
 		if !updated {
 			t.Fatal("want to be updated")
 		}
 	})
+
+	t.Run("multiple links fail together", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.GetterUpdater{
+			GetTreeHandler: emptyTree,
+			GetFileHandler: func(f *github.File) error {
+				f.Content = got
+
+				return nil
+			},
+			CommitHandler: func(github.Repo, github.Branch, string, []github.Change) (github.Commit, error) {
+				return github.Commit{}, errTest
+			},
+		}
+
+		l := &Links{
+			{
+				From: github.File{Content: "from"},
+				To:   github.File{Path: "a", Content: "to"},
+			},
+			{
+				From: github.File{Content: "from"},
+				To:   github.File{Path: "b", Content: "to"},
+			},
+		}
+
+		updated := l.Update(t.Context(), g, fmock.New(), head)
+
+		for _, link := range *l {
+			if link.Status != StatusFailedToUpdate {
+				t.Fatalf("want status %q, got %q", StatusFailedToUpdate, link.Status)
+			}
+		}
+
+		if updated {
+			t.Fatal("want to not be updated")
+		}
+	})
+}
+
+func TestLinksNeedUpdateOn(t *testing.T) {
+	t.Parallel()
+
+	base := github.Branch{Name: "main"}
+
+	g := gmock.Getter{
+		FileHandler: func(f *github.File) error {
+			if f.Ref != base.Name {
+				t.Fatalf("want file on %q, got %q", base.Name, f.Ref)
+			}
+
+			switch f.Path {
+			case "missing":
+				return github.ErrMissingFile
+			case "error":
+				return errTest
+			}
+
+			f.Content = "base"
+
+			return nil
+		},
+		TreeHandler: emptyTree,
+	}
+
+	tests := []struct {
+		name       string
+		link       Link
+		want       bool
+		wantStatus Status
+		wantErr    error
+	}{
+		{
+			name:       "base is up to date",
+			link:       Link{From: github.File{Content: "base"}, To: github.File{Path: "a"}, Status: StatusUpdateNotNeeded},
+			want:       false,
+			wantStatus: StatusUpdateNotNeeded,
+		},
+		{
+			name:       "update is on the head branch",
+			link:       Link{From: github.File{Content: "from"}, To: github.File{Path: "a"}, Status: StatusUpdateNotNeeded},
+			want:       true,
+			wantStatus: StatusPending,
+		},
+		{
+			name: "base differs",
+			link: Link{From: github.File{Content: "from"}, To: github.File{Path: "a", Content: "from"}},
+			want: true,
+		},
+		{
+			name: "file is missing on base",
+			link: Link{From: github.File{Content: "from"}, To: github.File{Path: "missing"}},
+			want: true,
+		},
+		{
+			name: "file is deleted on base",
+			link: Link{SourceMissing: true, To: github.File{Path: "missing"}},
+			want: false,
+		},
+		{
+			name: "file is not deleted on base",
+			link: Link{SourceMissing: true, To: github.File{Path: "a"}},
+			want: true,
+		},
+		{
+			name: "link failed",
+			link: Link{From: github.File{Content: "base"}, To: github.File{Path: "a"}, Status: StatusFailedToCheck},
+			want: true,
+		},
+		{
+			name: "binary can't be transformed",
+			link: Link{
+				From:      github.File{Content: "\x00", Binary: true},
+				To:        github.File{Path: "error"},
+				Transform: transform.Transforms{transform.Prepend{Text: "> "}},
+			},
+			want: false,
+		},
+		{
+			name:    "fails to get the file",
+			link:    Link{From: github.File{Content: "from"}, To: github.File{Path: "error"}},
+			wantErr: errTest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &Links{&test.link}
+
+			got, err := l.NeedUpdateOn(t.Context(), g, base)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("want error %v, got %v", test.wantErr, err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+
+			if test.wantStatus != "" && test.link.Status != test.wantStatus {
+				t.Fatalf("want status %q, got %q", test.wantStatus, test.link.Status)
+			}
+		})
+	}
+}
+
+func TestLinksPopulateToOn(t *testing.T) {
+	t.Parallel()
+
+	b := github.Branch{Name: "docs"}
+
+	t.Run("reads the files on the branch", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(f *github.File) error {
+				if f.Ref != b.Name {
+					t.Fatalf("want file on %q, got %q", b.Name, f.Ref)
+				}
+
+				if f.Path == "missing" {
+					return github.ErrMissingFile
+				}
+
+				f.Content = "on docs"
+
+				return nil
+			},
+			TreeHandler: func(github.Repo, string) (github.Tree, error) {
+				return github.Tree{Entries: []github.TreeEntry{{Path: "a", Mode: github.ModeExecutable}}}, nil
+			},
+		}
+
+		l := &Links{
+			{To: github.File{Path: "a", Content: "on main", Mode: github.ModeFile}},
+			{To: github.File{Path: "missing", Content: "on main"}},
+		}
+
+		if err := l.PopulateToOn(t.Context(), g, b); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if to := (*l)[0].To; to.Content != "on docs" || to.Mode != github.ModeExecutable || to.Ref != b.Name {
+			t.Fatalf("want the file on the branch, got %#v", to)
+		}
+
+		if to := (*l)[1].To; to.Content != "" || to.Ref != b.Name {
+			t.Fatalf("want an empty file on the branch, got %#v", to)
+		}
+	})
+
+	t.Run("fails to get a file", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(*github.File) error { return errTest },
+		}
+
+		l := &Links{{To: github.File{Path: "a"}}}
+
+		if err := l.PopulateToOn(t.Context(), g, b); !errors.Is(err, errMissingTo) {
+			t.Fatalf("expected error %v, got %v", errMissingTo, err)
+		}
+	})
 }
 
 func TestGroups(t *testing.T) {
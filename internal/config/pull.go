@@ -0,0 +1,104 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"text/template"
+)
+
+var errInvalidPull = errors.New("invalid pull")
+
+// Pull holds the settings of the pull requests opened for a group. Title, body
+// and branch are templates rendered with the group's links.
+type Pull struct {
+	Title         string   `json:"title"          yaml:"title"`
+	Body          string   `json:"body"           yaml:"body"`
+	Branch        string   `json:"branch"         yaml:"branch"`
+	Labels        []string `json:"labels"         yaml:"labels"`
+	Assignees     []string `json:"assignees"      yaml:"assignees"`
+	Reviewers     []string `json:"reviewers"      yaml:"reviewers"`
+	TeamReviewers []string `json:"team_reviewers" yaml:"team_reviewers"`
+	Milestone     int      `json:"milestone"      yaml:"milestone"`
+	Draft         *bool    `json:"draft"          yaml:"draft"`
+
+	// Comment is a template for the comment added when an existing pull
+	// request is refreshed, rendered with the links updated by the run. No
+	// comment is added if it's empty.
+	Comment string `json:"comment" yaml:"comment"`
+}
+
+// IsDraft reports whether the pull request is opened as a draft.
+func (p Pull) IsDraft() bool {
+	return p.Draft != nil && *p.Draft
+}
+
+// pullTemplates are the keys of the pull settings that are templates.
+//
+//nolint:gochecknoglobals // Used as a constant.
+var pullTemplates = []string{"title", "body", "branch", "comment"}
+
+// template returns the template of the pull setting with the key.
+func (p Pull) template(k string) string {
+	switch k {
+	case "title":
+		return p.Title
+	case "body":
+		return p.Body
+	case "branch":
+		return p.Branch
+	case "comment":
+		return p.Comment
+	default:
+		return ""
+	}
+}
+
+func (p Pull) validate() error {
+	if p.Milestone < 0 {
+		return fmt.Errorf("%w: milestone %d: want a milestone number", errInvalidPull, p.Milestone)
+	}
+
+	for _, k := range pullTemplates {
+		if err := validateTemplate(p.template(k)); err != nil {
+			return fmt.Errorf("%w: %s: %w", errInvalidPull, k, err)
+		}
+	}
+
+	return nil
+}
+
+// validateTemplate parses the template, without executing it.
+func validateTemplate(s string) error {
+	_, err := template.New("").Parse(s)
+
+	return err //nolint:wrapcheck // The error is wrapped by the caller.
+}
+
+// merge returns a copy of the pull settings with the values of o taking
+// precedence. Lists are replaced, not appended, so a group can clear them.
+func (p Pull) merge(o Pull) Pull {
+	p.Title = cmp.Or(o.Title, p.Title)
+	p.Body = cmp.Or(o.Body, p.Body)
+	p.Branch = cmp.Or(o.Branch, p.Branch)
+	p.Milestone = cmp.Or(o.Milestone, p.Milestone)
+	p.Comment = cmp.Or(o.Comment, p.Comment)
+
+	for _, l := range []struct{ dst, src *[]string }{
+		{&p.Labels, &o.Labels},
+		{&p.Assignees, &o.Assignees},
+		{&p.Reviewers, &o.Reviewers},
+		{&p.TeamReviewers, &o.TeamReviewers},
+	} {
+		if *l.src != nil {
+			*l.dst = slices.Clone(*l.src)
+		}
+	}
+
+	if o.Draft != nil {
+		p.Draft = o.Draft
+	}
+
+	return p
+}
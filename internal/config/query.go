@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nobe4/gh-ln/internal/glob"
+	"github.com/nobe4/gh-ln/pkg/github"
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+const reposKey = "repos"
+
+var (
+	errInvalidRepoQuery = errors.New("invalid repos query")
+	errUnknownKey       = errors.New("unknown key")
+	errInvalidValue     = errors.New("invalid value")
+)
+
+// RepoQuery selects repositories from an owner. Empty fields match everything.
+type RepoQuery struct {
+	Name       string   `json:"name"       yaml:"name"` // Glob pattern.
+	Topics     []string `json:"topics"     yaml:"topics"`
+	Visibility string   `json:"visibility" yaml:"visibility"`
+	Language   string   `json:"language"   yaml:"language"`
+	Archived   *bool    `json:"archived"   yaml:"archived"`
+	Fork       *bool    `json:"fork"       yaml:"fork"`
+}
+
+// parseRepoQuery reads the `repos` query from a `to` mapping, e.g.
+//
+//	to:
+//	  owner: acme
+//	  repos:
+//	    topic: go-service
+//
+// It returns nil if there is no query.
+func parseRepoQuery(rawTo any) (*RepoQuery, error) {
+	m, ok := rawTo.(map[string]any)
+	if !ok {
+		return nil, nil //nolint:nilnil // No query is a valid result.
+	}
+
+	rawQuery, ok := m[reposKey]
+	if !ok {
+		return nil, nil //nolint:nilnil // No query is a valid result.
+	}
+
+	rq, ok := rawQuery.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w, want a mapping, got %v (%T)",
+			errInvalidRepoQuery, errInvalidValue, rawQuery, rawQuery)
+	}
+
+	// Archived repositories cannot be updated, so they are skipped unless
+	// explicitly asked for.
+	archived := false
+	q := &RepoQuery{Archived: &archived}
+
+	for k, v := range rq {
+		if err := q.set(k, v); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidRepoQuery, err)
+		}
+	}
+
+	log.Debug("Parse repo query", "raw", rq, "parsed", q)
+
+	return q, nil
+}
+
+//nolint:revive // This function doesn't need to be simplified.
+func (q *RepoQuery) set(k string, v any) error {
+	var ok bool
+
+	switch k {
+	case "name":
+		q.Name, ok = v.(string)
+
+	case "topic", "topics":
+		q.Topics, ok = parseStrings(v)
+
+	case "visibility":
+		q.Visibility, ok = v.(string)
+
+	case "language":
+		q.Language, ok = v.(string)
+
+	case "archived":
+		var b bool
+		b, ok = v.(bool)
+		q.Archived = &b
+
+	case "fork":
+		var b bool
+		b, ok = v.(bool)
+		q.Fork = &b
+
+	default:
+		return fmt.Errorf("%w %q", errUnknownKey, k)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w for %q: %v (%T)", errInvalidValue, k, v, v)
+	}
+
+	return nil
+}
+
+// Match reports whether the repository matches all the query's fields.
+func (q *RepoQuery) Match(r github.RepoInfo) (bool, error) {
+	if q.Name != "" {
+		ok, err := glob.Match(q.Name, r.Name)
+		if err != nil || !ok {
+			return false, err //nolint:wrapcheck // The error is descriptive enough.
+		}
+	}
+
+	for _, t := range q.Topics {
+		if !slices.Contains(r.Topics, t) {
+			return false, nil
+		}
+	}
+
+	return (q.Visibility == "" || strings.EqualFold(q.Visibility, r.Visibility)) &&
+		(q.Language == "" || strings.EqualFold(q.Language, r.Language)) &&
+		(q.Archived == nil || *q.Archived == r.Archived) &&
+		(q.Fork == nil || *q.Fork == r.Fork), nil
+}
+
+func parseStrings(v any) ([]string, bool) {
+	switch v := v.(type) {
+	case string:
+		return []string{v}, true
+
+	case []any:
+		out := []string{}
+
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return nil, false
+			}
+
+			out = append(out, s)
+		}
+
+		return out, true
+
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nobe4/gh-ln/pkg/github"
+)
+
+func TestParseRepoQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no query", func(t *testing.T) {
+		t.Parallel()
+
+		for _, raw := range []any{nil, "to", map[string]any{"repo": "r"}} {
+			q, err := parseRepoQuery(raw)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if q != nil {
+				t.Fatalf("expected no query, got %+v", q)
+			}
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		t.Parallel()
+
+		for _, raw := range []any{
+			map[string]any{"repos": "r"},
+			map[string]any{"repos": map[string]any{"unknown": "r"}},
+			map[string]any{"repos": map[string]any{"name": 1}},
+			map[string]any{"repos": map[string]any{"topic": []any{1}}},
+			map[string]any{"repos": map[string]any{"fork": "yes"}},
+		} {
+			_, err := parseRepoQuery(raw)
+			if !errors.Is(err, errInvalidRepoQuery) {
+				t.Fatalf("expected error %v for %v, got %v", errInvalidRepoQuery, raw, err)
+			}
+		}
+	})
+
+	t.Run("parses a query", func(t *testing.T) {
+		t.Parallel()
+
+		q, err := parseRepoQuery(map[string]any{
+			"owner": "o",
+			"repos": map[string]any{
+				"name":       "a-*",
+				"topics":     []any{"t1", "t2"},
+				"visibility": "public",
+				"language":   "Go",
+				"fork":       true,
+			},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if q.Name != "a-*" || len(q.Topics) != 2 || q.Visibility != "public" || q.Language != "Go" {
+			t.Fatalf("unexpected query %+v", q)
+		}
+
+		if q.Archived == nil || *q.Archived {
+			t.Fatalf("expected archived to default to false, got %v", q.Archived)
+		}
+
+		if q.Fork == nil || !*q.Fork {
+			t.Fatalf("expected fork to be true, got %v", q.Fork)
+		}
+	})
+}
+
+func TestRepoQueryMatch(t *testing.T) {
+	t.Parallel()
+
+	yes, no := true, false
+
+	r := github.RepoInfo{
+		Name:       "go-api",
+		Topics:     []string{"go-service", "api"},
+		Visibility: "private",
+		Language:   "Go",
+	}
+
+	tests := []struct {
+		name  string
+		query RepoQuery
+		want  bool
+	}{
+		{name: "empty", want: true},
+		{name: "name", query: RepoQuery{Name: "go-*"}, want: true},
+		{name: "other name", query: RepoQuery{Name: "js-*"}},
+		{name: "topic", query: RepoQuery{Topics: []string{"api"}}, want: true},
+		{name: "topics", query: RepoQuery{Topics: []string{"api", "go-service"}}, want: true},
+		{name: "missing topic", query: RepoQuery{Topics: []string{"api", "web"}}},
+		{name: "visibility", query: RepoQuery{Visibility: "Private"}, want: true},
+		{name: "other visibility", query: RepoQuery{Visibility: "public"}},
+		{name: "language", query: RepoQuery{Language: "go"}, want: true},
+		{name: "other language", query: RepoQuery{Language: "rust"}},
+		{name: "not archived", query: RepoQuery{Archived: &no}, want: true},
+		{name: "archived", query: RepoQuery{Archived: &yes}},
+		{name: "not fork", query: RepoQuery{Fork: &no}, want: true},
+		{name: "fork", query: RepoQuery{Fork: &yes}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.query.Match(r)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
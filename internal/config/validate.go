@@ -0,0 +1,280 @@
+package config
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+
+	"github.com/nobe4/gh-ln/internal/glob"
+	"github.com/nobe4/gh-ln/internal/schema"
+	"github.com/nobe4/gh-ln/internal/semver"
+	"github.com/nobe4/gh-ln/pkg/github"
+)
+
+//go:embed schema.json
+var schemaJSON []byte
+
+//nolint:gochecknoglobals // The schema is parsed once.
+var parseSchema = sync.OnceValues(func() (*schema.Schema, error) {
+	return schema.Parse(schemaJSON)
+})
+
+// Problem is an issue found in a config file, at a given position.
+type Problem struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+// Validate checks a config file without making any API call.
+//
+// The file is checked against the JSON schema, then the defaults, the includes
+// and each link are parsed as they would be, and the link strings, refs and
+// patterns are checked. All the problems found are returned, ordered by
+// position.
+func Validate(source github.File, repo github.Repo) []Problem {
+	f, err := parser.ParseBytes([]byte(source.Content), 0)
+	if err != nil {
+		return []Problem{problemFromError(err)}
+	}
+
+	if len(f.Docs) > 1 {
+		line, column := schema.Position(f.Docs[1])
+
+		return []Problem{{Line: line, Column: column, Message: "want a single document"}}
+	}
+
+	if len(f.Docs) == 0 {
+		return nil
+	}
+
+	s, err := parseSchema()
+	if err != nil {
+		return []Problem{{Message: err.Error()}}
+	}
+
+	body := f.Docs[0].Body
+	errs := s.Validate(body)
+	problems := make([]Problem, 0, len(errs))
+
+	for _, e := range errs {
+		problems = append(problems, Problem{Line: e.Line, Column: e.Column, Message: e.Path + ": " + e.Message})
+	}
+
+	v := validator{
+		config: New(source, repo),
+		errs:   errs,
+	}
+
+	problems = append(problems, v.validate(body)...)
+
+	slices.SortStableFunc(problems, func(a, b Problem) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+
+		return a.Column - b.Column
+	})
+
+	return problems
+}
+
+// validator runs the static checks on the parts of the config that match the
+// schema.
+type validator struct {
+	config *Config
+	errs   []schema.Error
+}
+
+func (v validator) validate(body ast.Node) []Problem {
+	problems := []Problem{}
+
+	if n := value(body, "defaults"); n != nil && v.valid("$.defaults") {
+		pull := v.validatePull(value(n, "pull"), "$.defaults.pull")
+		problems = append(problems, pull...)
+
+		raw := RawDefaults{}
+
+		err := yaml.NodeToValue(n, &raw, yaml.Strict())
+		if err == nil {
+			err = v.config.parseDefaults(raw)
+		}
+
+		if err != nil && (len(pull) == 0 || !errors.Is(err, errInvalidPull)) {
+			problems = append(problems, problemAt(n, "$.defaults", err))
+		}
+	}
+
+	if n, ok := value(body, "groups").(*ast.MappingNode); ok {
+		for _, mv := range n.Values {
+			p := "$.groups." + mv.Key.GetToken().Value
+
+			if !v.valid(p) {
+				continue
+			}
+
+			pull := v.validatePull(value(mv.Value, "pull"), p+".pull")
+			problems = append(problems, pull...)
+
+			raw := RawGroup{}
+
+			err := yaml.NodeToValue(mv.Value, &raw, yaml.Strict())
+			if err == nil {
+				_, err = parseGroup(raw)
+			}
+
+			if err != nil && (len(pull) == 0 || !errors.Is(err, errInvalidPull)) {
+				problems = append(problems, problemAt(mv.Value, p, err))
+			}
+		}
+	}
+
+	if n, ok := value(body, "include").(*ast.SequenceNode); ok {
+		for i, item := range n.Values {
+			p := fmt.Sprintf("$.include[%d]", i)
+
+			if s, ok := item.(*ast.StringNode); ok && v.valid(p) {
+				if _, err := v.config.parseInclude(s.Value); err != nil {
+					problems = append(problems, problemAt(item, p, err))
+				}
+			}
+		}
+	}
+
+	if n, ok := value(body, "links").(*ast.SequenceNode); ok {
+		for i, item := range n.Values {
+			p := fmt.Sprintf("$.links[%d]", i)
+
+			if _, ok := item.(*ast.NullNode); ok || !v.valid(p) {
+				continue
+			}
+
+			if err := v.validateLink(item); err != nil {
+				problems = append(problems, problemAt(item, p, err))
+			}
+		}
+	}
+
+	return problems
+}
+
+func (v validator) validateLink(n ast.Node) error {
+	raw := RawLink{}
+	if err := yaml.NodeToValue(n, &raw, yaml.Strict()); err != nil {
+		return err //nolint:wrapcheck // The error is reported as is.
+	}
+
+	links, err := v.config.parseLink(raw)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, l := range links {
+		errs = append(errs, l.check())
+	}
+
+	return errors.Join(errs...)
+}
+
+// validatePull parses the pull templates, to report their errors at their
+// position.
+func (v validator) validatePull(n ast.Node, p string) []Problem {
+	problems := []Problem{}
+
+	for _, k := range pullTemplates {
+		t := value(n, k)
+
+		var s string
+		if t == nil || !v.valid(p+"."+k) || yaml.NodeToValue(t, &s) != nil {
+			continue
+		}
+
+		if err := validateTemplate(s); err != nil {
+			problems = append(problems, problemAt(t, p+"."+k, err))
+		}
+	}
+
+	return problems
+}
+
+// valid reports whether the schema found no error at or under the path.
+func (v validator) valid(p string) bool {
+	return !slices.ContainsFunc(v.errs, func(e schema.Error) bool {
+		rest, ok := strings.CutPrefix(e.Path, p)
+
+		return ok && (rest == "" || rest[0] == '.' || rest[0] == '[')
+	})
+}
+
+// check runs the checks that are otherwise done when populating the link.
+func (l *Link) check() error {
+	if semver.IsRange(l.From.Ref) {
+		if _, err := semver.ParseRange(l.From.Ref); err != nil {
+			return fmt.Errorf("%w %s: %w", errInvalidFrom, l.From, err)
+		}
+	}
+
+	if glob.IsPattern(l.From.Path) {
+		if err := glob.Validate(l.From.Path); err != nil {
+			return fmt.Errorf("%w %s: %w", errInvalidFrom, l.From, err)
+		}
+	}
+
+	return nil
+}
+
+// value returns the value of the key in the mapping, or nil.
+func value(n ast.Node, k string) ast.Node {
+	m, ok := n.(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+
+	for _, mv := range m.Values {
+		if mv.Key.GetToken().Value == k {
+			return mv.Value
+		}
+	}
+
+	return nil
+}
+
+func problemAt(n ast.Node, p string, err error) Problem {
+	line, column := schema.Position(n)
+
+	return Problem{Line: line, Column: column, Message: p + ": " + message(err)}
+}
+
+func problemFromError(err error) Problem {
+	var yErr yaml.Error
+	if errors.As(err, &yErr) {
+		if t := yErr.GetToken(); t != nil {
+			return Problem{Line: t.Position.Line, Column: t.Position.Column, Message: yErr.GetMessage()}
+		}
+	}
+
+	return Problem{Message: err.Error()}
+}
+
+// message returns the error message without the YAML source excerpt that
+// goccy/go-yaml adds.
+func message(err error) string {
+	var yErr yaml.Error
+	if errors.As(err, &yErr) {
+		return yErr.GetMessage()
+	}
+
+	return strings.ReplaceAll(err.Error(), "\n", "; ")
+}
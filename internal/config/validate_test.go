@@ -0,0 +1,195 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/nobe4/gh-ln/pkg/github"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	repo := github.Repo{
+		Repo:  "current_repo",
+		Owner: github.User{Login: "current_owner"},
+	}
+	source := github.File{
+		Path: ".ln-config.yaml",
+		Repo: repo,
+	}
+
+	t.Run("fixtures", func(t *testing.T) {
+		t.Parallel()
+
+		fs, err := fixtures.ReadDir("fixtures")
+		if err != nil {
+			t.Fatalf("failed to list fixtures: %v", err)
+		}
+
+		for _, f := range fs {
+			path := filepath.Join("fixtures", f.Name())
+
+			content, err := fixtures.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read fixtures %q: %v", path, err)
+			}
+
+			source := source
+			source.Content = string(content)
+
+			if got := Validate(source, repo); len(got) > 0 {
+				t.Errorf("%s: want no problem, but got %v", path, got)
+			}
+		}
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "empty",
+			content: "",
+			want:    []string{},
+		},
+		{
+			name:    "syntax error",
+			content: "links:\n  - from: [a\n",
+			want:    []string{"2:11: sequence end token ']' not found"},
+		},
+		{
+			name: "unknown keys",
+			content: `
+defaults:
+  links: {}
+links:
+  - from: a
+    to: b
+    tos: c
+`,
+			want: []string{
+				`3:3: $.defaults.links: unknown key "links"`,
+				`7:5: $.links[0].tos: unknown key "tos"`,
+			},
+		},
+		{
+			name: "wrong types and values",
+			content: `
+links:
+  - from: a
+    to: b
+    render: yes please
+  - from: a
+    to: b
+    on_source_missing: ignore
+  - from:
+      - path: a
+        branch: main
+    to: b
+`,
+			want: []string{
+				`5:13: $.links[0].render: want boolean, got string`,
+				`8:24: $.links[1].on_source_missing: want one of "fail", "skip", "delete", got ignore`,
+				`11:9: $.links[2].from[0].branch: unknown key "branch"`,
+			},
+		},
+		{
+			name: "invalid groups",
+			content: `
+groups:
+  not-a-repo:
+    vars: {}
+`,
+			want: []string{
+				`3:3: $.groups.not-a-repo: invalid key: "not-a-repo" does not match "^[\\w.-]+/[\\w.-]+$"`,
+			},
+		},
+		{
+			name: "invalid link strings",
+			content: `
+links:
+  - from: a
+    to: b
+  - from: owner/repo:a@^1.x.y
+    to: b
+  - from: a/[b.md
+    to: b
+  - from: a
+    to: b
+    transform:
+      - replace:
+          pattern: "("
+`,
+			want: []string{
+				`5:5: $.links[1]: from is invalid owner/repo:a@^1.x.y: invalid range "^1.x.y": invalid version "1.x.y"`,
+				"7:5: $.links[2]: from is invalid current_owner/current_repo:a/[b.md@: " +
+					`invalid pattern "a/[b.md": syntax error in pattern`,
+				`9:5: $.links[3]: invalid transform 0: invalid transform: replace.pattern "(": ` +
+					"error parsing regexp: missing closing ): `(`",
+			},
+		},
+		{
+			name: "invalid templates",
+			content: `
+links:
+  - from: a
+    to: '{{ .Missing }'
+`,
+			want: []string{
+				`3:5: $.links[0]: failed to apply template to "To.Path": invalid template: "{{ .Missing }": ` +
+					`template: :1: unexpected "}" in operand`,
+			},
+		},
+		{
+			name: "invalid pull templates",
+			content: `
+defaults:
+  pull:
+    title: '{{ len .Data }'
+groups:
+  owner/repo:
+    pull:
+      body: |
+        Files:
+        {{ range .Data }}
+      branch: sync
+`,
+			want: []string{
+				`4:12: $.defaults.pull.title: template: :1: unexpected "}" in operand`,
+				`8:13: $.groups.owner/repo.pull.body: template: :3: unexpected EOF`,
+			},
+		},
+		{
+			name: "invalid includes",
+			content: `
+include:
+  - a.yaml
+  - 'owner/repo:'
+`,
+			want: []string{
+				`4:5: $.include[1]: invalid include "owner/repo:": want 'path' or 'owner/repo:path@ref'`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := source
+			source.Content = test.content
+
+			got := []string{}
+			for _, p := range Validate(source, repo) {
+				got = append(got, p.String())
+			}
+
+			if !slices.Equal(test.want, got) {
+				t.Errorf("want\n%q\nbut got\n%q", test.want, got)
+			}
+		})
+	}
+}
@@ -26,12 +26,15 @@ func Parse() (environment.Environment, error) {
 	//revive:disable:line-length-limit // For flags, it's ok
 	flag.BoolVar(&e.Noop, "noop", false, "Execute in no-op mode")
 	flag.BoolVar(&e.Debug, "debug", false, "Enable debug mode")
+	flag.BoolVar(&e.GraphQL, "graphql", false, "Read the files of the links in batches, with GraphQL")
+	flag.IntVar(&e.Concurrency, "concurrency", 1, "Number of groups processed, and links populated, at once")
 
 	flag.StringVar(&e.Token, "token", os.Getenv("GITHUB_TOKEN"), "GitHub token to use, defaults to GITHUB_TOKEN")
 	flag.StringVar(&e.Server, "server", environment.DefaultServer, "GitHub server URL")
 	flag.StringVar(&e.Endpoint, "endpoint", environment.DefaultEndpoint, "GitHub API endpoint")
 	flag.StringVar(&e.Config, "config", environment.DefaultConfig, "Path to the config file on the specified repo")
 	flag.StringVar(&e.LocalConfig, "local-config", "", "Path to the local config file")
+	flag.StringVar(&e.CacheDir, "cache-dir", "", "Directory to cache the API responses in, revalidated on each run")
 	flag.StringVar(&e.App.ID, "app-id", os.Getenv("INPUT_APP_ID"), "GitHub App ID, defaults to INPUT_APP_ID")
 	flag.StringVar(&e.App.PrivateKey, "app-private-key", os.Getenv("INPUT_APP_PRIVATE_KEY"), "GitHub App private key, defaults to INPUT_APP_PRIVATE_KEY")
 	flag.StringVar(&e.App.InstallID, "app-install-id", os.Getenv("INPUT_APP_INSTALL_ID"), "GitHub App installation ID, defaults to INPUT_APP_INSTALL_ID")
@@ -39,10 +42,34 @@ func Parse() (environment.Environment, error) {
 	unsafeRepo := flag.String("repo", "", "GitHub repository where the config is stored")
 	//revive:enable:line-length-limit
 
+	flag.Usage = usage
+
 	flag.Parse()
 
+	if e.Concurrency < 1 {
+		return e, fmt.Errorf("%w -concurrency: want at least 1, got %d", ErrFlag, e.Concurrency)
+	}
+
+	if flag.Arg(0) == environment.CommandValidate {
+		e.Command = environment.CommandValidate
+
+		// Parse the flags given after the command.
+		if err := flag.CommandLine.Parse(flag.Args()[1:]); err != nil {
+			return e, fmt.Errorf("%w: %w", ErrFlag, err)
+		}
+
+		e.Args = flag.Args()
+	} else if flag.NArg() > 0 {
+		return e, fmt.Errorf("%w: unknown command %q", ErrFlag, flag.Arg(0))
+	}
+
 	repo, err := environment.ParseRepo(*unsafeRepo)
 	if err != nil {
+		// Validating doesn't need a repository.
+		if e.Command == environment.CommandValidate && *unsafeRepo == "" {
+			return e, nil
+		}
+
 		return e, fmt.Errorf("%w -repo: %w", ErrFlag, err)
 	}
 
@@ -50,3 +77,19 @@ func Parse() (environment.Environment, error) {
 
 	return e, nil
 }
+
+func usage() {
+	out := flag.CommandLine.Output()
+
+	//nolint:errcheck // Nothing to do if printing the usage fails.
+	fmt.Fprintf(out, `Usage:
+  gh ln [flags]
+      Sync the links of the config.
+  gh ln validate [flags] [files...]
+      Validate the config files, defaults to -local-config or -config, without calling the API.
+
+Flags:
+`)
+
+	flag.PrintDefaults()
+}
@@ -0,0 +1,79 @@
+/*
+Package glob implements matching of slash-separated paths against shell
+patterns.
+
+The syntax is the one of path.Match, with the addition of `**` as a full path
+segment, which matches zero or more directories.
+*/
+package glob
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+const doubleStar = "**"
+
+var ErrInvalidPattern = errors.New("invalid pattern")
+
+// IsPattern reports whether s contains any pattern meta character.
+func IsPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// Match reports whether name matches the pattern.
+func Match(pattern, name string) (bool, error) {
+	ok, err := match(strings.Split(pattern, "/"), strings.Split(name, "/"))
+	if err != nil {
+		return false, fmt.Errorf("%w %q: %w", ErrInvalidPattern, pattern, err)
+	}
+
+	return ok, nil
+}
+
+// Validate reports whether the pattern is malformed.
+func Validate(pattern string) error {
+	for _, p := range strings.Split(pattern, "/") {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidPattern, pattern, err)
+		}
+	}
+
+	return nil
+}
+
+func match(pattern, name []string) (bool, error) {
+	for len(pattern) > 0 {
+		if pattern[0] == doubleStar {
+			return matchDoubleStar(pattern[1:], name)
+		}
+
+		if len(name) == 0 {
+			return false, nil
+		}
+
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false, err //nolint:wrapcheck // Wrapped in Match.
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0, nil
+}
+
+// matchDoubleStar tries to match the rest of the pattern against all the
+// possible suffixes of name.
+func matchDoubleStar(pattern, name []string) (bool, error) {
+	for i := range len(name) + 1 {
+		ok, err := match(pattern, name[i:])
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,100 @@
+package glob
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{},
+		{s: "a/b.txt"},
+		{s: "a/*.txt", want: true},
+		{s: "a/?.txt", want: true},
+		{s: "a/[ab].txt", want: true},
+		{s: "**/a.txt", want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.s, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsPattern(test.s); got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{want: true},
+
+		{pattern: "a.txt", name: "a.txt", want: true},
+		{pattern: "a.txt", name: "b.txt"},
+		{pattern: "*.txt", name: "a.txt", want: true},
+		{pattern: "*.txt", name: "a/b.txt"},
+		{pattern: "a/*.txt", name: "a/b.txt", want: true},
+		{pattern: "a/lint-*.yml", name: "a/lint-go.yml", want: true},
+		{pattern: "a/lint-*.yml", name: "a/test-go.yml"},
+
+		{pattern: "**", name: "a", want: true},
+		{pattern: "**", name: "a/b/c", want: true},
+		{pattern: "**/*.md", name: "a.md", want: true},
+		{pattern: "**/*.md", name: "a/b/c.md", want: true},
+		{pattern: "docs/**/*.md", name: "docs/a.md", want: true},
+		{pattern: "docs/**/*.md", name: "docs/a/b/c.md", want: true},
+		{pattern: "docs/**/*.md", name: "docs/a/b/c.txt"},
+		{pattern: "docs/**/*.md", name: "other/a.md"},
+		{pattern: "docs/**", name: "docs", want: true},
+		{pattern: "a/**/b/**/c", name: "a/x/b/y/z/c", want: true},
+		{pattern: "a/**/b/**/c", name: "a/x/y/z/c"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Match(test.pattern, test.name)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+
+	t.Run("fails with an invalid pattern", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Match("a/[", "a/b")
+		if !errors.Is(err, ErrInvalidPattern) {
+			t.Fatalf("expected error %v, got %v", ErrInvalidPattern, err)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := Validate("docs/**/*.md"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := Validate("a/[b/c.md"); !errors.Is(err, ErrInvalidPattern) {
+		t.Fatalf("want %v, got %v", ErrInvalidPattern, err)
+	}
+}
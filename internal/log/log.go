@@ -40,7 +40,12 @@ func (h *Handler) Enabled(_ context.Context, l slog.Level) bool {
 	return l >= h.opts.Level.Level()
 }
 
+// Handle writes the record. The indentation is shared by all the records, so
+// concurrent callers should log through a buffer, see log.WithBuffer.
 func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	var level string
 
 	switch r.Level {
@@ -94,12 +99,11 @@ func (h *Handler) WithGroup(_ string) slog.Handler {
 }
 
 func (h *Handler) write(p []byte) error {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	_, err := h.out.Write(p)
+	if _, err := h.out.Write(p); err != nil {
+		return fmt.Errorf("%w: %w", log.ErrCannotWrite, err)
+	}
 
-	return fmt.Errorf("%w: %w", log.ErrCannotWrite, err)
+	return nil
 }
 
 func (*Handler) formatAttrs(r slog.Record) string {
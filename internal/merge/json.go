@@ -0,0 +1,164 @@
+package merge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+const defaultIndent = "  "
+
+// toJSON converts a YAML document to indented JSON, keeping the key order and
+// the number literals.
+func toJSON(s, indent string) (string, error) {
+	doc, err := parse(s)
+	if err != nil {
+		return "", fmt.Errorf("%w merged document: %w", ErrParse, err)
+	}
+
+	v, err := toValue(doc.Body)
+	if err != nil {
+		return "", err
+	}
+
+	compact := &bytes.Buffer{}
+	if err := writeJSON(compact, v); err != nil {
+		return "", err
+	}
+
+	out := &bytes.Buffer{}
+	if err := json.Indent(out, compact.Bytes(), "", indent); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidMerge, err)
+	}
+
+	return out.String(), nil
+}
+
+// toValue converts a node to a value for writeJSON. The mappings are kept in
+// order, and the numbers as they are written, e.g. `1.50` is not turned into
+// `1.5`.
+func toValue(n ast.Node) (any, error) {
+	switch n := n.(type) {
+	case *ast.MappingNode:
+		m := make(yaml.MapSlice, 0, len(n.Values))
+
+		for _, mv := range n.Values {
+			v, err := toValue(mv.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			m = append(m, yaml.MapItem{Key: key(mv), Value: v})
+		}
+
+		return m, nil
+
+	case *ast.MappingValueNode:
+		v, err := toValue(n.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return yaml.MapSlice{{Key: key(n), Value: v}}, nil
+
+	case *ast.SequenceNode:
+		s := make([]any, 0, len(n.Values))
+
+		for _, e := range n.Values {
+			v, err := toValue(e)
+			if err != nil {
+				return nil, err
+			}
+
+			s = append(s, v)
+		}
+
+		return s, nil
+
+	case *ast.IntegerNode, *ast.FloatNode:
+		if lit := n.GetToken().Value; json.Valid([]byte(lit)) {
+			return json.Number(lit), nil
+		}
+	}
+
+	var v any
+	if err := yaml.NodeToValue(n, &v, yaml.UseOrderedMap()); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMerge, err)
+	}
+
+	return v, nil
+}
+
+// writeJSON writes a value as compact JSON. encoding/json sorts the keys of
+// maps, so the ordered maps are written by hand.
+func writeJSON(b *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		b.WriteByte('{')
+
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+
+			if err := writeJSON(b, fmt.Sprint(item.Key)); err != nil {
+				return err
+			}
+
+			b.WriteByte(':')
+
+			if err := writeJSON(b, item.Value); err != nil {
+				return err
+			}
+		}
+
+		b.WriteByte('}')
+
+	case []any:
+		b.WriteByte('[')
+
+		for i, e := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+
+			if err := writeJSON(b, e); err != nil {
+				return err
+			}
+		}
+
+		b.WriteByte(']')
+
+	default:
+		// NOTE: The strings are kept as is, e.g. `&&` in a script is not
+		// escaped as `\u0026\u0026`.
+		e := json.NewEncoder(b)
+		e.SetEscapeHTML(false)
+
+		if err := e.Encode(v); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidMerge, err)
+		}
+
+		// Encode ends the value with a newline.
+		b.Truncate(b.Len() - 1)
+	}
+
+	return nil
+}
+
+// indentOf guesses the indentation of a JSON document from its first indented
+// line.
+func indentOf(s string) string {
+	for _, l := range strings.Split(s, "\n") {
+		trimmed := strings.TrimLeft(l, " \t")
+		if trimmed != "" && len(trimmed) < len(l) {
+			return l[:len(l)-len(trimmed)]
+		}
+	}
+
+	return defaultIndent
+}
@@ -0,0 +1,389 @@
+/*
+Package merge implements structured merges of YAML and JSON documents: the keys
+of a source document are merged into a destination document, instead of
+replacing it.
+
+The destination is edited in place through the goccy/go-yaml AST, so its
+comments and key order are kept. New keys are appended to their mapping.
+*/
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+type Strategy string
+
+const (
+	// StrategyDeep merges the mappings recursively, the source values win on
+	// conflicts. It is the default.
+	StrategyDeep Strategy = "deep"
+	// StrategySource replaces the destination values with the source ones,
+	// without merging them.
+	StrategySource Strategy = "source"
+	// StrategyDestination merges the mappings recursively, the destination
+	// values win on conflicts. I.e. only the missing keys are added.
+	StrategyDestination Strategy = "destination"
+)
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+const keySeparator = "."
+
+var (
+	ErrInvalidMerge = errors.New("invalid merge")
+	ErrParse        = errors.New("failed to parse")
+)
+
+type Merge struct {
+	Strategy Strategy `json:"strategy" yaml:"strategy"`
+
+	// Keys restricts the merge to these key paths, e.g. `linters.enable`.
+	// All the keys are merged if it is empty.
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+
+	// Format is the format of the documents, it is guessed from the file
+	// extension if empty.
+	Format Format `json:"format,omitempty" yaml:"format,omitempty"`
+}
+
+// selection is a tree of the keys to merge. A nil selection selects all the
+// keys, and its values are merged according to the strategy.
+type selection map[string]selection
+
+// Validate checks the merge and sets its default values.
+func (m *Merge) Validate() error {
+	switch m.Strategy {
+	case "":
+		m.Strategy = StrategyDeep
+
+	case StrategyDeep, StrategySource, StrategyDestination:
+
+	default:
+		return fmt.Errorf("%w: unknown strategy %q, want %q, %q or %q",
+			ErrInvalidMerge, m.Strategy, StrategyDeep, StrategySource, StrategyDestination)
+	}
+
+	switch m.Format {
+	case "", FormatYAML, FormatJSON:
+
+	default:
+		return fmt.Errorf("%w: unknown format %q, want %q or %q",
+			ErrInvalidMerge, m.Format, FormatYAML, FormatJSON)
+	}
+
+	for _, k := range m.Keys {
+		if slices.Contains(strings.Split(k, keySeparator), "") {
+			return fmt.Errorf("%w: invalid key path %q", ErrInvalidMerge, k)
+		}
+	}
+
+	return nil
+}
+
+// FormatOf guesses the format of a file from its extension. It defaults to
+// YAML, which is a superset of JSON.
+func FormatOf(p string) Format {
+	if strings.EqualFold(path.Ext(p), ".json") {
+		return FormatJSON
+	}
+
+	return FormatYAML
+}
+
+// Apply merges the source document into the destination one. The destination
+// is returned unchanged if the merge doesn't modify it.
+func (m Merge) Apply(dest, src string) (string, error) {
+	destDoc, err := parse(dest)
+	if err != nil {
+		return "", fmt.Errorf("%w destination: %w", ErrParse, err)
+	}
+
+	srcDoc, err := parse(src)
+	if err != nil {
+		return "", fmt.Errorf("%w source: %w", ErrParse, err)
+	}
+
+	if !m.mergeDocument(destDoc, srcDoc) {
+		return dest, nil
+	}
+
+	out := destDoc.String()
+
+	if m.Format == FormatJSON {
+		if out, err = toJSON(out, indentOf(dest)); err != nil {
+			return "", err
+		}
+	}
+
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+
+	return out, nil
+}
+
+// mergeDocument merges the source document into the destination one, and
+// reports whether the destination changed.
+func (m Merge) mergeDocument(dest, src *ast.DocumentNode) bool {
+	sel := m.selection()
+
+	srcMap, srcIsMap := src.Body.(*ast.MappingNode)
+	if srcIsMap && sel != nil {
+		srcMap.Values = prune(srcMap.Values, sel)
+	}
+
+	if isEmpty(dest.Body) {
+		if isEmpty(src.Body) || (srcIsMap && len(srcMap.Values) == 0) {
+			return false
+		}
+
+		dest.Body = src.Body
+
+		return true
+	}
+
+	destMap, destIsMap := dest.Body.(*ast.MappingNode)
+	if !destIsMap || !srcIsMap {
+		if m.Strategy == StrategyDestination || isEmpty(src.Body) || equal(dest.Body, src.Body) {
+			return false
+		}
+
+		dest.Body = src.Body
+
+		return true
+	}
+
+	return m.mergeMapping(destMap, srcMap, sel)
+}
+
+func (m Merge) selection() selection {
+	if len(m.Keys) == 0 {
+		return nil
+	}
+
+	sel := selection{}
+
+	for _, k := range m.Keys {
+		s := sel
+
+		parts := strings.Split(k, keySeparator)
+		for i, p := range parts {
+			child, ok := s[p]
+
+			switch {
+			case i == len(parts)-1:
+				// The whole value is selected.
+				s[p] = nil
+
+			case ok && child == nil:
+				// A parent is already selected as a whole.
+
+			case !ok:
+				child = selection{}
+				s[p] = child
+			}
+
+			if child == nil {
+				break
+			}
+
+			s = child
+		}
+	}
+
+	return sel
+}
+
+// mergeMapping merges the selected source values into the destination
+// mapping, and reports whether it changed.
+func (m Merge) mergeMapping(dest, src *ast.MappingNode, sel selection) bool {
+	changed := false
+
+	for _, sv := range src.Values {
+		k := key(sv)
+
+		var sub selection
+
+		if sel != nil {
+			s, ok := sel[k]
+			if !ok {
+				continue
+			}
+
+			sub = s
+		}
+
+		dv := find(dest, k)
+		if dv == nil {
+			appendValue(dest, sv)
+
+			changed = true
+
+			continue
+		}
+
+		if sub != nil {
+			dMap, dOk := dv.Value.(*ast.MappingNode)
+			sMap, sOk := sv.Value.(*ast.MappingNode)
+
+			if dOk && sOk {
+				changed = m.mergeMapping(dMap, sMap, sub) || changed
+
+				continue
+			}
+		}
+
+		changed = m.mergeValue(dv, sv) || changed
+	}
+
+	return changed
+}
+
+// mergeValue merges a source value into the destination one, according to
+// the strategy, and reports whether it changed.
+func (m Merge) mergeValue(dest, src *ast.MappingValueNode) bool {
+	if m.Strategy != StrategySource {
+		dMap, dOk := dest.Value.(*ast.MappingNode)
+		sMap, sOk := src.Value.(*ast.MappingNode)
+
+		if dOk && sOk {
+			return m.mergeMapping(dMap, sMap, nil)
+		}
+
+		if m.Strategy == StrategyDestination {
+			return false
+		}
+	}
+
+	if equal(dest.Value, src.Value) {
+		return false
+	}
+
+	replaceValue(dest, src)
+
+	return true
+}
+
+// prune removes the values that are not selected, recursively.
+func prune(values []*ast.MappingValueNode, sel selection) []*ast.MappingValueNode {
+	kept := []*ast.MappingValueNode{}
+
+	for _, v := range values {
+		sub, ok := sel[key(v)]
+		if !ok {
+			continue
+		}
+
+		if sub != nil {
+			m, ok := v.Value.(*ast.MappingNode)
+			if !ok {
+				continue
+			}
+
+			if m.Values = prune(m.Values, sub); len(m.Values) == 0 {
+				continue
+			}
+		}
+
+		kept = append(kept, v)
+	}
+
+	return kept
+}
+
+func parse(s string) (*ast.DocumentNode, error) {
+	f, err := parser.ParseBytes([]byte(s), parser.ParseComments)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // The error is wrapped by the caller.
+	}
+
+	switch len(f.Docs) {
+	case 0:
+		return ast.Document(nil, nil), nil
+
+	case 1:
+		return f.Docs[0], nil
+
+	default:
+		return nil, fmt.Errorf("%w: found %d documents, want 1", ErrInvalidMerge, len(f.Docs))
+	}
+}
+
+func isEmpty(n ast.Node) bool {
+	if n == nil {
+		return true
+	}
+
+	_, ok := n.(*ast.CommentGroupNode)
+
+	return ok
+}
+
+func key(v *ast.MappingValueNode) string {
+	return v.Key.GetToken().Value
+}
+
+func find(m *ast.MappingNode, k string) *ast.MappingValueNode {
+	for _, v := range m.Values {
+		if key(v) == k {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// appendValue adds the source value at the end of the mapping, indented as
+// its other keys.
+func appendValue(m *ast.MappingNode, v *ast.MappingValueNode) {
+	if len(m.Values) > 0 {
+		v.AddColumn(column(m.Values[0]) - column(v))
+	}
+
+	m.Values = append(m.Values, v)
+}
+
+// replaceValue sets the source value in the destination one, keeping the
+// source value's indentation relative to its key, and the destination
+// value's comment.
+func replaceValue(dest, src *ast.MappingValueNode) {
+	src.Value.AddColumn(column(dest) - column(src))
+
+	if c := dest.Value.GetComment(); c != nil && src.Value.GetComment() == nil {
+		//nolint:errcheck,gosec // Setting a comment on a value never fails.
+		src.Value.SetComment(c)
+	}
+
+	dest.Value = src.Value
+}
+
+// equal reports whether the nodes have the same value, regardless of their
+// formatting and comments.
+func equal(a, b ast.Node) bool {
+	var va, vb any
+
+	if yaml.NodeToValue(a, &va) != nil || yaml.NodeToValue(b, &vb) != nil {
+		return a.String() == b.String()
+	}
+
+	return reflect.DeepEqual(va, vb)
+}
+
+func column(v *ast.MappingValueNode) int {
+	return v.Key.GetToken().Position.Column
+}
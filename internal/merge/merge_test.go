@@ -0,0 +1,326 @@
+package merge
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets the defaults", func(t *testing.T) {
+		t.Parallel()
+
+		m := Merge{}
+		if err := m.Validate(); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if m.Strategy != StrategyDeep {
+			t.Fatalf("want strategy %q, got %q", StrategyDeep, m.Strategy)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		merge Merge
+	}{
+		{name: "unknown strategy", merge: Merge{Strategy: "x"}},
+		{name: "unknown format", merge: Merge{Format: "toml"}},
+		{name: "empty key", merge: Merge{Keys: []string{"a..b"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := test.merge.Validate(); !errors.Is(err, ErrInvalidMerge) {
+				t.Fatalf("want error %v, got %v", ErrInvalidMerge, err)
+			}
+		})
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want Format
+	}{
+		{path: "renovate.json", want: FormatJSON},
+		{path: "a/b.JSON", want: FormatJSON},
+		{path: ".golangci.yml", want: FormatYAML},
+		{path: "config", want: FormatYAML},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatOf(test.path); got != test.want {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+//nolint:maintidx // Long table of test cases.
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	const dest = `# Header comment.
+run:
+  timeout: 5m # Inline comment.
+linters:
+  # Section comment.
+  enable:
+    - errcheck
+  settings:
+    lll:
+      line-length: 80
+`
+
+	const src = `linters:
+  enable:
+    - govet
+  settings:
+    lll:
+      line-length: 120
+    revive:
+      rules: []
+issues:
+  max-same: 0
+`
+
+	tests := []struct {
+		name  string
+		merge Merge
+		dest  string
+		src   string
+		want  string
+	}{
+		{
+			name:  "deep",
+			merge: Merge{Strategy: StrategyDeep},
+			dest:  dest,
+			src:   src,
+			want: `# Header comment.
+run:
+  timeout: 5m # Inline comment.
+linters:
+  # Section comment.
+  enable:
+    - govet
+  settings:
+    lll:
+      line-length: 120
+    revive:
+      rules: []
+issues:
+  max-same: 0
+`,
+		},
+		{
+			name:  "source",
+			merge: Merge{Strategy: StrategySource},
+			dest:  "a:\n  b: 1\n  c: 2\nd: 3\n",
+			src:   "a:\n  b: 2\n",
+			want:  "a:\n  b: 2\nd: 3\n",
+		},
+		{
+			name:  "destination",
+			merge: Merge{Strategy: StrategyDestination},
+			dest:  dest,
+			src:   src,
+			want: `# Header comment.
+run:
+  timeout: 5m # Inline comment.
+linters:
+  # Section comment.
+  enable:
+    - errcheck
+  settings:
+    lll:
+      line-length: 80
+    revive:
+      rules: []
+issues:
+  max-same: 0
+`,
+		},
+		{
+			name:  "keys",
+			merge: Merge{Strategy: StrategyDeep, Keys: []string{"linters.settings.revive", "issues"}},
+			dest:  dest,
+			src:   src,
+			want: `# Header comment.
+run:
+  timeout: 5m # Inline comment.
+linters:
+  # Section comment.
+  enable:
+    - errcheck
+  settings:
+    lll:
+      line-length: 80
+    revive:
+      rules: []
+issues:
+  max-same: 0
+`,
+		},
+		{
+			name:  "keys with source",
+			merge: Merge{Strategy: StrategySource, Keys: []string{"a.b"}},
+			dest:  "a:\n  b:\n    c: 1\n    d: 2\n  e: 3\n",
+			src:   "a:\n  b:\n    c: 2\n  e: 4\n",
+			want:  "a:\n  b:\n    c: 2\n  e: 3\n",
+		},
+		{
+			name:  "missing keys",
+			merge: Merge{Strategy: StrategyDeep, Keys: []string{"x.y"}},
+			dest:  dest,
+			src:   src,
+			want:  dest,
+		},
+		{
+			name:  "indentation",
+			merge: Merge{Strategy: StrategyDeep},
+			dest:  "a:\n    b: 1\n",
+			src:   "a:\n  c:\n    d: 1\n",
+			want:  "a:\n    b: 1\n    c:\n      d: 1\n",
+		},
+		{
+			name:  "same content",
+			merge: Merge{Strategy: StrategyDeep},
+			dest:  "a:   1 # Odd spacing.\n",
+			src:   "a: 1\n",
+			want:  "a:   1 # Odd spacing.\n",
+		},
+		{
+			name:  "replaced comment",
+			merge: Merge{Strategy: StrategyDeep},
+			dest:  "a: 1 # Comment.\nb: 2\n",
+			src:   "a: 2\n",
+			want:  "a: 2 # Comment.\nb: 2\n",
+		},
+		{
+			name:  "empty destination",
+			merge: Merge{Strategy: StrategyDeep, Keys: []string{"a"}},
+			dest:  "",
+			src:   "a: 1\nb: 2\n",
+			want:  "a: 1\n",
+		},
+		{
+			name:  "empty source",
+			merge: Merge{Strategy: StrategyDeep},
+			dest:  "a: 1\n",
+			src:   "",
+			want:  "a: 1\n",
+		},
+		{
+			name:  "not a mapping",
+			merge: Merge{Strategy: StrategyDeep},
+			dest:  "a: 1\n",
+			src:   "- a\n",
+			want:  "- a\n",
+		},
+		{
+			name:  "json",
+			merge: Merge{Strategy: StrategyDeep, Format: FormatJSON},
+			dest: `{
+    "extends": ["config:base"],
+    "labels": ["deps"],
+    "schedule": "daily"
+}
+`,
+			src: `{"labels": ["dependencies"], "automerge": true, "packageRules": [{"matchPackagePatterns": ["*"]}]}`,
+			want: `{
+    "extends": [
+        "config:base"
+    ],
+    "labels": [
+        "dependencies"
+    ],
+    "schedule": "daily",
+    "automerge": true,
+    "packageRules": [
+        {
+            "matchPackagePatterns": [
+                "*"
+            ]
+        }
+    ]
+}
+`,
+		},
+		{
+			name:  "json keeps the untouched values",
+			merge: Merge{Strategy: StrategyDeep, Format: FormatJSON},
+			dest:  "{\n  \"scripts\": {\"build\": \"a && b > c\", \"tag\": \"<br>\"},\n  \"version\": 1.50\n}\n",
+			src:   `{"private": true}`,
+			want: `{
+  "scripts": {
+    "build": "a && b > c",
+    "tag": "<br>"
+  },
+  "version": 1.50,
+  "private": true
+}
+`,
+		},
+		{
+			name:  "json same content",
+			merge: Merge{Strategy: StrategyDeep, Format: FormatJSON},
+			dest:  "{\n\t\"a\": {\"b\": 1}\n}\n",
+			src:   `{"a": {"b": 1}}`,
+			want:  "{\n\t\"a\": {\"b\": 1}\n}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.merge.Apply(test.dest, test.src)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want\n%s\ngot\n%s", test.want, got)
+			}
+
+			// Merging again changes nothing.
+			again, err := test.merge.Apply(got, test.src)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if again != got {
+				t.Fatalf("want idempotent merge\n%s\ngot\n%s", got, again)
+			}
+		})
+	}
+
+	t.Run("fails to parse", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := (Merge{}).Apply("a: [", "a: 1"); !errors.Is(err, ErrParse) {
+			t.Fatalf("want error %v, got %v", ErrParse, err)
+		}
+
+		if _, err := (Merge{}).Apply("a: 1", "a: ["); !errors.Is(err, ErrParse) {
+			t.Fatalf("want error %v, got %v", ErrParse, err)
+		}
+	})
+
+	t.Run("fails on multiple documents", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := (Merge{}).Apply("a: 1\n---\nb: 2\n", "a: 1"); !errors.Is(err, ErrInvalidMerge) {
+			t.Fatalf("want error %v, got %v", ErrInvalidMerge, err)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+/*
+Package pool runs tasks concurrently, with a bounded number of workers.
+*/
+package pool
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+// Run calls fn for each index from 0 to n-1, with at most size calls running
+// at once.
+// Each call logs into its own buffer, which is flushed once all the previous
+// calls are done. This way, the logs are written in the order of the indexes,
+// whatever the scheduling.
+// It returns the errors of the calls, by index.
+func Run(ctx context.Context, size, n int, fn func(context.Context, int) error) []error {
+	var (
+		errs    = make([]error, n)
+		buffers = make([]*log.Buffer, n)
+		indexes = make(chan int)
+		next    = 0
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+	)
+
+	for range max(1, min(size, n)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				bctx, b := log.WithBuffer(ctx)
+				err := fn(bctx, i)
+
+				mu.Lock()
+
+				errs[i], buffers[i] = err, b
+
+				for ; next < n && buffers[next] != nil; next++ {
+					buffers[next].Flush(ctx)
+				}
+
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range n {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
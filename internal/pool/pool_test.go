@@ -0,0 +1,114 @@
+package pool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+var errTest = errors.New("test")
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		size int
+		n    int
+	}{
+		{size: 0, n: 3},
+		{size: 1, n: 3},
+		{size: 2, n: 10},
+		{size: 10, n: 2},
+		{size: 2, n: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d workers for %d tasks", test.size, test.n), func(t *testing.T) {
+			t.Parallel()
+
+			var running, peak atomic.Int32
+
+			errs := Run(t.Context(), test.size, test.n, func(_ context.Context, i int) error {
+				r := running.Add(1)
+				defer running.Add(-1)
+
+				for {
+					p := peak.Load()
+					if r <= p || peak.CompareAndSwap(p, r) {
+						break
+					}
+				}
+
+				time.Sleep(time.Millisecond)
+
+				if i%2 == 1 {
+					return fmt.Errorf("%w %d", errTest, i)
+				}
+
+				return nil
+			})
+
+			if len(errs) != test.n {
+				t.Fatalf("want %d errors, got %d", test.n, len(errs))
+			}
+
+			for i, err := range errs {
+				if (i%2 == 1) != errors.Is(err, errTest) {
+					t.Fatalf("want error for odd tasks, got %v for %d", err, i)
+				}
+			}
+
+			if p := int(peak.Load()); p > max(1, test.size) {
+				t.Fatalf("want at most %d tasks at once, got %d", max(1, test.size), p)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // It replaces the default logger.
+func TestRunLogsInOrder(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(out, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == slog.LevelKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})))
+
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	Run(t.Context(), 3, 3, func(ctx context.Context, i int) error {
+		// The last task finishes first.
+		time.Sleep(time.Duration(3-i) * 10 * time.Millisecond)
+
+		log.InfoContext(ctx, "start", "task", i)
+		log.InfoContext(ctx, "end", "task", i)
+
+		return nil
+	})
+
+	want := []string{
+		"msg=start task=0", "msg=end task=0",
+		"msg=start task=1", "msg=end task=1",
+		"msg=start task=2", "msg=end task=2",
+	}
+
+	got := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if !slices.Equal(want, got) {
+		t.Fatalf("want logs %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,524 @@
+/*
+Package schema validates YAML documents against a JSON Schema, reporting the
+position of each problem in the document.
+
+Only the subset of JSON Schema (draft 2020-12) needed for the configuration is
+implemented:
+
+  - $ref to the local $defs
+  - type, enum
+  - properties, additionalProperties, propertyNames, required,
+    minProperties, maxProperties
+  - items, minItems
+  - pattern, minLength
+  - anyOf, oneOf
+*/
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+)
+
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeNull    = "null"
+
+	defsPrefix = "#/$defs/"
+)
+
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// Schema is a JSON Schema. `true` and `false` are valid schemas, that accept
+// everything and nothing.
+type Schema struct {
+	Ref  string             `json:"$ref"`
+	Defs map[string]*Schema `json:"$defs"`
+
+	Description string `json:"description"`
+
+	Type Types `json:"type"`
+	Enum []any `json:"enum"`
+
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *Schema            `json:"additionalProperties"`
+	PropertyNames        *Schema            `json:"propertyNames"`
+	Required             []string           `json:"required"`
+	MinProperties        *int               `json:"minProperties"`
+	MaxProperties        *int               `json:"maxProperties"`
+
+	Items    *Schema `json:"items"`
+	MinItems *int    `json:"minItems"`
+
+	Pattern   string `json:"pattern"`
+	MinLength *int   `json:"minLength"`
+
+	AnyOf []*Schema `json:"anyOf"`
+	OneOf []*Schema `json:"oneOf"`
+
+	never   bool
+	pattern *regexp.Regexp
+}
+
+// Types is the `type` of a schema, either a single type or a list.
+type Types []string
+
+// Error is a problem found in the document, at the position of the faulty
+// node.
+type Error struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// Parse parses a schema and compiles its patterns.
+func Parse(b []byte) (*Schema, error) {
+	s := &Schema{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+
+	if err := s.compile(s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	var accept bool
+	if err := json.Unmarshal(b, &accept); err == nil {
+		*s = Schema{never: !accept}
+
+		return nil
+	}
+
+	// The alias prevents an infinite recursion.
+	type alias Schema
+
+	a := alias{}
+	if err := json.Unmarshal(b, &a); err != nil {
+		return err //nolint:wrapcheck // The error is wrapped by Parse.
+	}
+
+	*s = Schema(a)
+
+	return nil
+}
+
+func (t *Types) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*t = Types{single}
+
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(b, &multiple); err != nil {
+		return err //nolint:wrapcheck // The error is wrapped by Parse.
+	}
+
+	*t = multiple
+
+	return nil
+}
+
+// compile compiles the patterns and checks the references, recursively.
+func (s *Schema) compile(root *Schema) error {
+	if s.Ref != "" {
+		if _, err := root.resolve(s.Ref); err != nil {
+			return err
+		}
+	}
+
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%w: pattern %q: %w", ErrInvalidSchema, s.Pattern, err)
+		}
+
+		s.pattern = re
+	}
+
+	for _, c := range s.children() {
+		if err := c.compile(root); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) children() []*Schema {
+	children := []*Schema{s.AdditionalProperties, s.PropertyNames, s.Items}
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+
+	for _, c := range s.Properties {
+		children = append(children, c)
+	}
+
+	for _, c := range s.Defs {
+		children = append(children, c)
+	}
+
+	return slices.DeleteFunc(children, func(c *Schema) bool { return c == nil })
+}
+
+func (s *Schema) resolve(ref string) (*Schema, error) {
+	name, ok := strings.CutPrefix(ref, defsPrefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported $ref %q, want %s<name>", ErrInvalidSchema, ref, defsPrefix)
+	}
+
+	d, ok := s.Defs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown $ref %q", ErrInvalidSchema, ref)
+	}
+
+	return d, nil
+}
+
+// Validate validates the node against the schema, and returns all the errors
+// found.
+func (s *Schema) Validate(n ast.Node) []Error {
+	v := validator{root: s}
+
+	return v.validate(s, n, "$")
+}
+
+type validator struct {
+	root *Schema
+}
+
+//nolint:revive // This function doesn't need to be simplified.
+func (v validator) validate(s *Schema, n ast.Node, p string) []Error {
+	n = unwrap(n)
+
+	if _, ok := n.(*ast.AliasNode); ok {
+		// Aliases are checked where their anchor is defined.
+		return nil
+	}
+
+	if s.never {
+		return []Error{newError(n, p, "is not allowed")}
+	}
+
+	if s.Ref != "" {
+		// References are checked when compiling.
+		ref, _ := v.root.resolve(s.Ref)
+
+		return v.validate(ref, n, p)
+	}
+
+	t := typeOf(n)
+
+	if len(s.Type) > 0 && !s.Type.accept(t) {
+		return []Error{newError(n, p, fmt.Sprintf("want %s, got %s", strings.Join(s.Type, " or "), t))}
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, n) {
+		return []Error{newError(n, p, fmt.Sprintf("want one of %s, got %s", formatEnum(s.Enum), scalar(n)))}
+	}
+
+	errs := []Error{}
+
+	if s.AnyOf != nil {
+		errs = append(errs, v.validateAnyOf(s.AnyOf, n, p, false)...)
+	}
+
+	if s.OneOf != nil {
+		errs = append(errs, v.validateAnyOf(s.OneOf, n, p, true)...)
+	}
+
+	switch t {
+	case TypeObject:
+		errs = append(errs, v.validateObject(s, n, p)...)
+
+	case TypeArray:
+		errs = append(errs, v.validateArray(s, n.(*ast.SequenceNode), p)...)
+
+	case TypeString:
+		errs = append(errs, v.validateString(s, n, p)...)
+	}
+
+	return errs
+}
+
+// validateAnyOf checks that the node matches one of the schemas, or exactly
+// one of them if `one` is true.
+// When it matches none, the errors of the schemas that accept the node's type
+// are reported, since they are the most likely to be meant.
+func (v validator) validateAnyOf(schemas []*Schema, n ast.Node, p string, one bool) []Error {
+	matches := 0
+	candidates := [][]Error{}
+
+	for _, s := range schemas {
+		errs := v.validate(s, n, p)
+		if len(errs) == 0 {
+			matches++
+
+			continue
+		}
+
+		if v.acceptsType(s, typeOf(unwrap(n))) {
+			candidates = append(candidates, errs)
+		}
+	}
+
+	switch {
+	case one && matches > 1:
+		return []Error{newError(n, p, fmt.Sprintf("matches %d forms, want exactly one", matches))}
+
+	case matches > 0:
+		return nil
+
+	case len(candidates) == 1:
+		return candidates[0]
+
+	default:
+		return []Error{newError(n, p, "does not match any of the allowed forms")}
+	}
+}
+
+func (v validator) acceptsType(s *Schema, t string) bool {
+	for s.Ref != "" {
+		s, _ = v.root.resolve(s.Ref)
+	}
+
+	if s.never {
+		return false
+	}
+
+	if len(s.Type) > 0 {
+		return s.Type.accept(t)
+	}
+
+	// Without a type, the schema accepts what its forms accept.
+	forms := slices.Concat(s.AnyOf, s.OneOf)
+
+	return len(forms) == 0 || slices.ContainsFunc(forms, func(f *Schema) bool {
+		return v.acceptsType(f, t)
+	})
+}
+
+//nolint:revive // This function doesn't need to be simplified.
+func (v validator) validateObject(s *Schema, n ast.Node, p string) []Error {
+	errs := []Error{}
+	values := mappingValues(n)
+	seen := map[string]bool{}
+
+	for _, mv := range values {
+		k := mv.Key.GetToken().Value
+		kp := p + "." + k
+		seen[k] = true
+
+		if s.PropertyNames != nil {
+			for _, e := range v.validate(s.PropertyNames, mv.Key, kp) {
+				e.Message = "invalid key: " + e.Message
+				errs = append(errs, e)
+			}
+		}
+
+		if ps, ok := s.Properties[k]; ok {
+			errs = append(errs, v.validate(ps, mv.Value, kp)...)
+
+			continue
+		}
+
+		if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.never {
+				errs = append(errs, newError(mv.Key, kp, fmt.Sprintf("unknown key %q", k)))
+
+				continue
+			}
+
+			errs = append(errs, v.validate(s.AdditionalProperties, mv.Value, kp)...)
+		}
+	}
+
+	for _, r := range s.Required {
+		if !seen[r] {
+			errs = append(errs, newError(n, p, fmt.Sprintf("missing key %q", r)))
+		}
+	}
+
+	if s.MinProperties != nil && len(values) < *s.MinProperties {
+		errs = append(errs, newError(n, p, fmt.Sprintf("want at least %d keys, got %d", *s.MinProperties, len(values))))
+	}
+
+	if s.MaxProperties != nil && len(values) > *s.MaxProperties {
+		errs = append(errs, newError(n, p, fmt.Sprintf("want at most %d keys, got %d", *s.MaxProperties, len(values))))
+	}
+
+	return errs
+}
+
+func (v validator) validateArray(s *Schema, n *ast.SequenceNode, p string) []Error {
+	errs := []Error{}
+
+	if s.MinItems != nil && len(n.Values) < *s.MinItems {
+		errs = append(errs, newError(n, p, fmt.Sprintf("want at least %d items, got %d", *s.MinItems, len(n.Values))))
+	}
+
+	if s.Items == nil {
+		return errs
+	}
+
+	for i, item := range n.Values {
+		errs = append(errs, v.validate(s.Items, item, fmt.Sprintf("%s[%d]", p, i))...)
+	}
+
+	return errs
+}
+
+func (validator) validateString(s *Schema, n ast.Node, p string) []Error {
+	value := scalar(n)
+
+	if s.MinLength != nil && len(value) < *s.MinLength {
+		return []Error{newError(n, p, fmt.Sprintf("want at least %d characters", *s.MinLength))}
+	}
+
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		return []Error{newError(n, p, fmt.Sprintf("%q does not match %q", value, s.Pattern))}
+	}
+
+	return nil
+}
+
+func (t Types) accept(got string) bool {
+	return slices.Contains(t, got) || (got == TypeInteger && slices.Contains(t, TypeNumber))
+}
+
+// unwrap returns the value of anchors and tags.
+func unwrap(n ast.Node) ast.Node {
+	for {
+		switch w := n.(type) {
+		case *ast.AnchorNode:
+			n = w.Value
+
+		case *ast.TagNode:
+			n = w.Value
+
+		default:
+			return n
+		}
+	}
+}
+
+func typeOf(n ast.Node) string {
+	switch n.(type) {
+	case *ast.MappingNode, *ast.MappingValueNode:
+		return TypeObject
+
+	case *ast.SequenceNode:
+		return TypeArray
+
+	case *ast.StringNode, *ast.LiteralNode:
+		return TypeString
+
+	case *ast.IntegerNode:
+		return TypeInteger
+
+	case *ast.FloatNode, *ast.InfinityNode, *ast.NanNode:
+		return TypeNumber
+
+	case *ast.BoolNode:
+		return TypeBoolean
+
+	default:
+		return TypeNull
+	}
+}
+
+func mappingValues(n ast.Node) []*ast.MappingValueNode {
+	switch n := n.(type) {
+	case *ast.MappingNode:
+		return n.Values
+
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+
+	default:
+		return nil
+	}
+}
+
+func scalar(n ast.Node) string {
+	switch n := n.(type) {
+	case nil:
+		return "null"
+
+	case *ast.StringNode:
+		return n.Value
+
+	case *ast.LiteralNode:
+		return n.Value.Value
+
+	case ast.ScalarNode:
+		return fmt.Sprint(n.GetValue())
+
+	default:
+		return typeOf(n)
+	}
+}
+
+func inEnum(enum []any, n ast.Node) bool {
+	value := scalar(n)
+
+	return slices.ContainsFunc(enum, func(e any) bool {
+		return fmt.Sprint(e) == value
+	})
+}
+
+func formatEnum(enum []any) string {
+	values := make([]string, 0, len(enum))
+	for _, e := range enum {
+		values = append(values, strconv.Quote(fmt.Sprint(e)))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func newError(n ast.Node, p, msg string) Error {
+	line, column := Position(n)
+
+	return Error{Path: p, Line: line, Column: column, Message: msg}
+}
+
+// Position returns the line and column where the node starts. A block mapping
+// starts at its first key, not at its first `:`.
+func Position(n ast.Node) (int, int) {
+	if m, ok := n.(*ast.MappingNode); ok && !m.IsFlowStyle && len(m.Values) > 0 {
+		n = m.Values[0].Key
+	}
+
+	if n == nil {
+		return 0, 0
+	}
+
+	t := n.GetToken()
+	if t == nil {
+		return 0, 0
+	}
+
+	return t.Position.Line, t.Position.Column
+}
@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goccy/go-yaml/parser"
+)
+
+const testSchema = `{
+	"type": "object",
+	"additionalProperties": false,
+	"required": ["name"],
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"kind": {"enum": ["a", "b"]},
+		"count": {"type": "number"},
+		"tags": {"type": "array", "minItems": 1, "items": {"type": "string", "pattern": "^[a-z]+$"}},
+		"labels": {
+			"type": "object",
+			"propertyNames": {"pattern": "^[a-z]+$"},
+			"additionalProperties": {"type": "string"}
+		},
+		"file": {"$ref": "#/$defs/file"},
+		"one": {"oneOf": [{"type": "string"}, {"type": "string", "pattern": "^x"}]},
+		"single": {"type": "object", "minProperties": 1, "maxProperties": 1}
+	},
+	"$defs": {
+		"file": {
+			"anyOf": [
+				{"type": "string"},
+				{"type": "object", "additionalProperties": false, "properties": {"path": {"type": "string"}}}
+			]
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  string
+		want []Error
+	}{
+		{
+			name: "valid",
+			doc: `
+name: n
+kind: a
+count: 1
+tags: [a, b]
+labels: {a: b}
+file: {path: p}
+one: y
+single: {a: 1}
+`,
+			want: []Error{},
+		},
+		{
+			name: "anchors and aliases",
+			doc:  "name: &n n\nlabels:\n  a: *n\n",
+			want: []Error{},
+		},
+		{
+			name: "type",
+			doc:  "name: [a]\n",
+			want: []Error{{Path: "$.name", Line: 1, Column: 7, Message: "want string, got array"}},
+		},
+		{
+			name: "required and unknown",
+			doc:  "kind: a\nother: 1\n",
+			want: []Error{
+				{Path: "$.other", Line: 2, Column: 1, Message: `unknown key "other"`},
+				{Path: "$", Line: 1, Column: 1, Message: `missing key "name"`},
+			},
+		},
+		{
+			name: "enum",
+			doc:  "name: n\nkind: c\n",
+			want: []Error{{Path: "$.kind", Line: 2, Column: 7, Message: `want one of "a", "b", got c`}},
+		},
+		{
+			name: "items",
+			doc:  "name: n\ntags:\n  - a\n  - B\n",
+			want: []Error{{Path: "$.tags[1]", Line: 4, Column: 5, Message: `"B" does not match "^[a-z]+$"`}},
+		},
+		{
+			name: "min items and length",
+			doc:  "name: ''\ntags: []\n",
+			want: []Error{
+				{Path: "$.name", Line: 1, Column: 7, Message: "want at least 1 characters"},
+				{Path: "$.tags", Line: 2, Column: 7, Message: "want at least 1 items, got 0"},
+			},
+		},
+		{
+			name: "property names",
+			doc:  "name: n\nlabels:\n  A: b\n",
+			want: []Error{{Path: "$.labels.A", Line: 3, Column: 3, Message: `invalid key: "A" does not match "^[a-z]+$"`}},
+		},
+		{
+			name: "any of with a single candidate",
+			doc:  "name: n\nfile:\n  other: p\n",
+			want: []Error{{Path: "$.file.other", Line: 3, Column: 3, Message: `unknown key "other"`}},
+		},
+		{
+			name: "any of without candidate",
+			doc:  "name: n\nfile: 1\n",
+			want: []Error{{Path: "$.file", Line: 2, Column: 7, Message: "does not match any of the allowed forms"}},
+		},
+		{
+			name: "one of",
+			doc:  "name: n\none: x\n",
+			want: []Error{{Path: "$.one", Line: 2, Column: 6, Message: "matches 2 forms, want exactly one"}},
+		},
+		{
+			name: "properties count",
+			doc:  "name: n\nsingle: {a: 1, b: 2}\n",
+			want: []Error{{Path: "$.single", Line: 2, Column: 9, Message: "want at most 1 keys, got 2"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := parser.ParseBytes([]byte(test.doc), 0)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			got := s.Validate(f.Docs[0].Body)
+
+			if len(got) != len(test.want) {
+				t.Fatalf("want %d errors, got %d: %v", len(test.want), len(got), got)
+			}
+
+			for i, w := range test.want {
+				if got[i] != w {
+					t.Fatalf("want error %d to be %+v, got %+v", i, w, got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"invalid JSON":    `{`,
+		"invalid type":    `{"type": 1}`,
+		"invalid pattern": `{"pattern": "["}`,
+		"unknown ref":     `{"$ref": "#/$defs/x"}`,
+		"remote ref":      `{"items": {"$ref": "https://example.com"}}`,
+	}
+
+	for name, s := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse([]byte(s)); !errors.Is(err, ErrInvalidSchema) {
+				t.Fatalf("want error %v, got %v", ErrInvalidSchema, err)
+			}
+		})
+	}
+}
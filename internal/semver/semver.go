@@ -0,0 +1,341 @@
+/*
+Package semver implements semantic versions and ranges, to resolve a range of
+versions to the highest matching tag.
+
+The range syntax follows npm's:
+
+	1.2.3, =1.2.3   exactly 1.2.3
+	1.2, 1.2.x      >=1.2.0 <1.3.0
+	>1.2.3, >=1.2   comparisons, joined with ',' or ' ' to intersect them
+	^1.4            >=1.4.0 <2.0.0, and >=0.4.0 <0.5.0 for ^0.4
+	~1.4.2          >=1.4.2 <1.5.0
+	*               any version
+
+A leading `v` is ignored. Prereleases only match a range that mentions a
+prerelease of the same version.
+*/
+package semver
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	opEQ = "="
+	opGT = ">"
+	opGE = ">="
+	opLT = "<"
+	opLE = "<="
+
+	operators = "^~<>="
+	partCount = 3
+)
+
+var (
+	ErrInvalidVersion = errors.New("invalid version")
+	ErrInvalidRange   = errors.New("invalid range")
+
+	versionRe = regexp.MustCompile(
+		`^v?(\d+)(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`,
+	)
+)
+
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string
+}
+
+// Range is a set of comparators that a version must all satisfy.
+type Range []comparator
+
+type comparator struct {
+	op string
+	v  Version
+}
+
+// partial is a version with possibly missing parts, e.g. `1.2` or `1.x`.
+type partial struct {
+	parts      []int
+	prerelease string
+}
+
+// Parse parses a complete version, e.g. `v1.2.3` or `1.2.3-rc.1`.
+func Parse(s string) (Version, error) {
+	p, err := parsePartial(s)
+	if err != nil {
+		return Version{}, err
+	}
+
+	if len(p.parts) != partCount {
+		return Version{}, fmt.Errorf("%w %q: want major.minor.patch", ErrInvalidVersion, s)
+	}
+
+	return p.lower(), nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+
+	return s
+}
+
+// Compare returns -1, 0 or 1 if v is lower, equal or greater than o.
+func (v Version) Compare(o Version) int {
+	if c := cmp.Compare(v.Major, o.Major); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+func (v Version) core() Version {
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// IsRange reports whether the string is meant as a range rather than a git
+// reference. Only ranges starting with an operator are recognized, since
+// `1.x` or `v1.2` are also common branch and tag names.
+func IsRange(s string) bool {
+	return s == "*" || strings.IndexAny(s, operators) == 0
+}
+
+// ParseRange parses a range, see the package documentation for the syntax.
+func ParseRange(s string) (Range, error) {
+	terms := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w %q: empty", ErrInvalidRange, s)
+	}
+
+	r := Range{}
+
+	for _, t := range terms {
+		c, err := parseTerm(t)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidRange, s, err)
+		}
+
+		r = append(r, c...)
+	}
+
+	return r, nil
+}
+
+// Match reports whether the version satisfies the range.
+func (r Range) Match(v Version) bool {
+	if v.Prerelease != "" && !r.allowsPrerelease(v) {
+		return false
+	}
+
+	for _, c := range r {
+		if !c.match(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Highest returns the highest tag that satisfies the range. Tags that are not
+// versions are ignored.
+func (r Range) Highest(tags []string) (string, bool) {
+	var (
+		best    string
+		bestV   Version
+		matched bool
+	)
+
+	for _, t := range tags {
+		v, err := Parse(t)
+		if err != nil || !r.Match(v) {
+			continue
+		}
+
+		if !matched || v.Compare(bestV) > 0 {
+			best, bestV, matched = t, v, true
+		}
+	}
+
+	return best, matched
+}
+
+func (r Range) allowsPrerelease(v Version) bool {
+	return slices.ContainsFunc(r, func(c comparator) bool {
+		return c.v.Prerelease != "" && c.v.core() == v.core()
+	})
+}
+
+func (c comparator) match(v Version) bool {
+	n := v.Compare(c.v)
+
+	switch c.op {
+	case opGT:
+		return n > 0
+	case opGE:
+		return n >= 0
+	case opLT:
+		return n < 0
+	case opLE:
+		return n <= 0
+	default:
+		return n == 0
+	}
+}
+
+//nolint:revive // This function doesn't need to be simplified.
+func parseTerm(t string) ([]comparator, error) {
+	if t == "*" {
+		return []comparator{{op: opGE, v: Version{}}}, nil
+	}
+
+	rest := strings.TrimLeft(t, operators)
+	op := t[:len(t)-len(rest)]
+
+	p, err := parsePartial(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	lower := p.lower()
+	complete := len(p.parts) == partCount
+
+	switch op {
+	case "", opEQ:
+		if complete {
+			return []comparator{{op: opEQ, v: lower}}, nil
+		}
+
+		return []comparator{{op: opGE, v: lower}, {op: opLT, v: p.upper(len(p.parts))}}, nil
+
+	case opGE:
+		return []comparator{{op: opGE, v: lower}}, nil
+
+	case opGT:
+		if complete {
+			return []comparator{{op: opGT, v: lower}}, nil
+		}
+
+		return []comparator{{op: opGE, v: p.upper(len(p.parts))}}, nil
+
+	case opLT:
+		return []comparator{{op: opLT, v: lower}}, nil
+
+	case opLE:
+		if complete {
+			return []comparator{{op: opLE, v: lower}}, nil
+		}
+
+		return []comparator{{op: opLT, v: p.upper(len(p.parts))}}, nil
+
+	case "^":
+		// The first non-zero part, or the last given one, can't change.
+		i := 0
+		for i < len(p.parts)-1 && p.parts[i] == 0 {
+			i++
+		}
+
+		return []comparator{{op: opGE, v: lower}, {op: opLT, v: p.upper(i + 1)}}, nil
+
+	case "~":
+		return []comparator{{op: opGE, v: lower}, {op: opLT, v: p.upper(min(len(p.parts), 2))}}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidRange, op)
+	}
+}
+
+func parsePartial(s string) (partial, error) {
+	m := versionRe.FindStringSubmatch(s)
+	if m == nil {
+		return partial{}, fmt.Errorf("%w %q", ErrInvalidVersion, s)
+	}
+
+	p := partial{prerelease: m[4]}
+
+	for _, part := range m[1:4] {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			// Empty or wildcard: the following parts are ignored.
+			break
+		}
+
+		p.parts = append(p.parts, n)
+	}
+
+	return p, nil
+}
+
+// lower is the lowest version of the partial, missing parts being 0.
+func (p partial) lower() Version {
+	parts := make([]int, partCount)
+	copy(parts, p.parts)
+
+	return Version{Major: parts[0], Minor: parts[1], Patch: parts[2], Prerelease: p.prerelease}
+}
+
+// upper is the lowest version above the partial, once its first n parts are
+// fixed. E.g. the upper bound of `1.2.3` with 2 fixed parts is `1.3.0`.
+func (p partial) upper(n int) Version {
+	parts := make([]int, partCount)
+	copy(parts, p.parts[:n])
+	parts[n-1]++
+
+	return Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}
+}
+
+// comparePrerelease compares prereleases as per https://semver.org/#spec-item-11.
+// A version without prerelease is greater than one with.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := range min(len(as), len(bs)) {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(as), len(bs))
+}
+
+// compareIdentifier compares numeric identifiers numerically, and lower than
+// alphanumeric ones, which are compared lexically.
+func compareIdentifier(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
@@ -0,0 +1,173 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  Version
+	}{
+		{input: "1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{input: "v1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{input: "v1.2.3-rc.1", want: Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1"}},
+		{input: "1.2.3+build.5", want: Version{Major: 1, Minor: 2, Patch: 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(test.input)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "main", "1.2", "1.x.3", "v1.2.3.4"} {
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(input); !errors.Is(err, ErrInvalidVersion) {
+				t.Fatalf("want error %v, got %v", ErrInvalidVersion, err)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	// In increasing order.
+	versions := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	for i := range len(versions) - 1 {
+		a, _ := Parse(versions[i])
+		b, _ := Parse(versions[i+1])
+
+		if a.Compare(b) != -1 || b.Compare(a) != 1 || a.Compare(a) != 0 {
+			t.Fatalf("want %s < %s", a, b)
+		}
+	}
+}
+
+func TestIsRange(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		"^1.4":    true,
+		"~1.4":    true,
+		">=1, <2": true,
+		"=1.2.3":  true,
+		"*":       true,
+		"main":    false,
+		"v1.2.3":  false,
+		"1.x":     false,
+		"":        false,
+	}
+
+	for input, want := range tests {
+		if got := IsRange(input); got != want {
+			t.Fatalf("want IsRange(%q) to be %v, got %v", input, want, got)
+		}
+	}
+}
+
+func TestRangeMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rng   string
+		match []string
+		miss  []string
+	}{
+		{rng: "*", match: []string{"0.0.0", "9.9.9"}, miss: []string{"1.0.0-rc.1"}},
+		{rng: "1.2.3", match: []string{"1.2.3"}, miss: []string{"1.2.4"}},
+		{rng: "=1.2", match: []string{"1.2.0", "1.2.9"}, miss: []string{"1.3.0", "1.1.9"}},
+		{rng: "1.x", match: []string{"1.0.0", "1.9.9"}, miss: []string{"2.0.0"}},
+		{rng: "^1.4", match: []string{"1.4.0", "1.9.0"}, miss: []string{"1.3.9", "2.0.0"}},
+		{rng: "^0.4", match: []string{"0.4.0", "0.4.9"}, miss: []string{"0.5.0"}},
+		{rng: "^0.0.3", match: []string{"0.0.3"}, miss: []string{"0.0.4"}},
+		{rng: "~1.4.2", match: []string{"1.4.2", "1.4.9"}, miss: []string{"1.4.1", "1.5.0"}},
+		{rng: "~1", match: []string{"1.0.0", "1.9.0"}, miss: []string{"2.0.0"}},
+		{rng: ">1.2", match: []string{"1.3.0"}, miss: []string{"1.2.9"}},
+		{rng: ">1.2.3", match: []string{"1.2.4"}, miss: []string{"1.2.3"}},
+		{rng: "<=1.2", match: []string{"1.2.9"}, miss: []string{"1.3.0"}},
+		{rng: "<=1.2.3", match: []string{"1.2.3"}, miss: []string{"1.2.4"}},
+		{rng: ">=1.2, <2", match: []string{"1.2.0", "1.9.9"}, miss: []string{"1.1.0", "2.0.0"}},
+		{rng: ">=1.0.0-rc.1", match: []string{"1.0.0-rc.2", "1.0.0"}, miss: []string{"1.0.0-beta", "1.1.0-rc.1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rng, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := ParseRange(test.rng)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			for _, s := range test.match {
+				if v, _ := Parse(s); !r.Match(v) {
+					t.Fatalf("want %q to match %s", test.rng, s)
+				}
+			}
+
+			for _, s := range test.miss {
+				if v, _ := Parse(s); r.Match(v) {
+					t.Fatalf("want %q not to match %s", test.rng, s)
+				}
+			}
+		})
+	}
+
+	for _, input := range []string{"", "^", "^main", "!1.2", ">=1, <x"} {
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ParseRange(input); !errors.Is(err, ErrInvalidRange) {
+				t.Fatalf("want error %v, got %v", ErrInvalidRange, err)
+			}
+		})
+	}
+}
+
+func TestHighest(t *testing.T) {
+	t.Parallel()
+
+	r, err := ParseRange("^1.4")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	tags := []string{"v1.3.0", "v1.4.0", "latest", "v1.10.1", "v1.9.0", "v1.11.0-rc.1", "v2.0.0"}
+
+	if got, ok := r.Highest(tags); !ok || got != "v1.10.1" {
+		t.Fatalf("want v1.10.1, got %q (%v)", got, ok)
+	}
+
+	if got, ok := r.Highest([]string{"v2.0.0"}); ok {
+		t.Fatalf("want no match, got %q", got)
+	}
+}
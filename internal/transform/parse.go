@@ -0,0 +1,145 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Parse reads the transformations from their raw YAML representation.
+func Parse(raw []map[string]any) (Transforms, error) {
+	t := Transforms{}
+
+	for i, rt := range raw {
+		tr, err := parseOne(rt)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidTransform, i, err)
+		}
+
+		t = append(t, tr)
+	}
+
+	return t, nil
+}
+
+//nolint:ireturn // Parsing into the interface is the point.
+func parseOne(raw map[string]any) (Transform, error) {
+	if len(raw) != 1 {
+		return nil, fmt.Errorf("%w: want exactly one key, got %v", ErrInvalidTransform, raw)
+	}
+
+	for k, v := range raw {
+		switch k {
+		case "replace":
+			return parseReplace(v)
+
+		case "prepend":
+			s, err := asString(k, v)
+
+			return Prepend{Text: s}, err
+
+		case "append":
+			s, err := asString(k, v)
+
+			return Append{Text: s}, err
+
+		case "strip":
+			return parseStrip(v)
+
+		case "indent":
+			return parseIndent(v)
+
+		default:
+			return nil, fmt.Errorf("%w: unknown transform %q", ErrInvalidTransform, k)
+		}
+	}
+
+	return nil, nil //nolint:nilnil // Unreachable, the map has exactly one key.
+}
+
+func parseReplace(v any) (Replace, error) {
+	m, err := asMap("replace", v)
+	if err != nil {
+		return Replace{}, err
+	}
+
+	r := Replace{}
+
+	if r.Pattern, err = asString("replace.pattern", m["pattern"]); err != nil {
+		return Replace{}, err
+	}
+
+	// NOTE: A missing `with` removes the matches.
+	if w, ok := m["with"]; ok {
+		if r.With, err = asString("replace.with", w); err != nil {
+			return Replace{}, err
+		}
+	}
+
+	if r.re, err = regexp.Compile(r.Pattern); err != nil {
+		return Replace{}, fmt.Errorf("%w: replace.pattern %q: %w", ErrInvalidTransform, r.Pattern, err)
+	}
+
+	return r, nil
+}
+
+func parseStrip(v any) (Strip, error) {
+	m, err := asMap("strip", v)
+	if err != nil {
+		return Strip{}, err
+	}
+
+	s := Strip{}
+
+	if s.Begin, err = asString("strip.begin", m["begin"]); err != nil {
+		return Strip{}, err
+	}
+
+	if s.End, err = asString("strip.end", m["end"]); err != nil {
+		return Strip{}, err
+	}
+
+	if s.Begin == "" || s.End == "" {
+		return Strip{}, fmt.Errorf("%w: strip needs both begin and end", ErrInvalidTransform)
+	}
+
+	return s, nil
+}
+
+// parseIndent accepts either a number of spaces or a prefix string.
+func parseIndent(v any) (Indent, error) {
+	switch v := v.(type) {
+	case string:
+		return Indent{Prefix: v}, nil
+
+	case int:
+		return Indent{Prefix: strings.Repeat(" ", max(v, 0))}, nil
+
+	case uint64:
+		return Indent{Prefix: strings.Repeat(" ", int(v))}, nil //nolint:gosec // Indentations are small.
+
+	case int64:
+		return Indent{Prefix: strings.Repeat(" ", int(max(v, 0)))}, nil
+
+	default:
+		return Indent{}, fmt.Errorf("%w: indent wants a number or a string, got %v (%T)", ErrInvalidTransform, v, v)
+	}
+}
+
+func asString(k string, v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s wants a string, got %v (%T)", ErrInvalidTransform, k, v, v)
+	}
+
+	return s, nil
+}
+
+func asMap(k string, v any) (map[string]any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s wants a mapping, got %v (%T)", ErrInvalidTransform, k, v, v)
+	}
+
+	return m, nil
+}
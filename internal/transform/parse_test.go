@@ -0,0 +1,57 @@
+package transform
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses all transforms", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := Parse([]map[string]any{
+			{"replace": map[string]any{"pattern": "a", "with": "b"}},
+			{"replace": map[string]any{"pattern": "c"}},
+			{"prepend": "d"},
+			{"append": "e"},
+			{"strip": map[string]any{"begin": "f", "end": "g"}},
+			{"indent": uint64(2)},
+			{"indent": "> "},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		out, err := got.Apply("a c\n")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if want := ">   db \n>   e"; out != want {
+			t.Fatalf("want %q, got %q", want, out)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		for _, raw := range []map[string]any{
+			{},
+			{"prepend": "a", "append": "b"},
+			{"unknown": "a"},
+			{"prepend": 1},
+			{"replace": "a"},
+			{"replace": map[string]any{"pattern": "("}},
+			{"replace": map[string]any{"pattern": "a", "with": 1}},
+			{"strip": map[string]any{"begin": "a"}},
+			{"indent": true},
+		} {
+			_, err := Parse([]map[string]any{raw})
+			if !errors.Is(err, ErrInvalidTransform) {
+				t.Fatalf("expected error %v for %v, got %v", ErrInvalidTransform, raw, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,130 @@
+/*
+Package transform implements the transformations that can be applied to a
+file's content before it is written to its destination.
+
+Transformations are configured as an ordered list of single-key mappings, e.g.
+
+	transform:
+	  - replace:
+	      pattern: "project-(\\w+)"
+	      with: "other-$1"
+	  - prepend: "# Synced file, do not edit.\n"
+	  - append: "\n# End of synced file.\n"
+	  - strip:
+	      begin: "# BEGIN private"
+	      end: "# END private"
+	  - indent: 2
+*/
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidTransform = errors.New("invalid transform")
+	ErrFailTransform    = errors.New("failed to transform")
+	ErrUnterminated     = errors.New("unterminated block")
+)
+
+type Transform interface {
+	Apply(in string) (string, error)
+}
+
+// Transforms is an ordered list of transformations, each one is applied to the
+// result of the previous one.
+type Transforms []Transform
+
+type Replace struct {
+	Pattern string `json:"pattern"`
+	With    string `json:"with"`
+
+	re *regexp.Regexp
+}
+
+type Prepend struct {
+	Text string `json:"text"`
+}
+
+type Append struct {
+	Text string `json:"text"`
+}
+
+// Strip removes the lines between the begin and end markers, the lines
+// containing the markers included.
+type Strip struct {
+	Begin string `json:"begin"`
+	End   string `json:"end"`
+}
+
+// Indent prefixes all the non-empty lines.
+type Indent struct {
+	Prefix string `json:"prefix"`
+}
+
+func (t Transforms) Apply(in string) (string, error) {
+	out := in
+
+	for i, tr := range t {
+		var err error
+
+		if out, err = tr.Apply(out); err != nil {
+			return "", fmt.Errorf("%w with transform %d (%T): %w", ErrFailTransform, i, tr, err)
+		}
+	}
+
+	return out, nil
+}
+
+func (r Replace) Apply(in string) (string, error) {
+	return r.re.ReplaceAllString(in, r.With), nil
+}
+
+func (p Prepend) Apply(in string) (string, error) {
+	return p.Text + in, nil
+}
+
+func (a Append) Apply(in string) (string, error) {
+	return in + a.Text, nil
+}
+
+func (s Strip) Apply(in string) (string, error) {
+	out := strings.Builder{}
+	inside := false
+
+	for line := range strings.Lines(in) {
+		switch {
+		case !inside && strings.Contains(line, s.Begin):
+			inside = true
+
+		case inside && strings.Contains(line, s.End):
+			inside = false
+
+		case !inside:
+			out.WriteString(line)
+		}
+	}
+
+	if inside {
+		return "", fmt.Errorf("%w: missing %q", ErrUnterminated, s.End)
+	}
+
+	return out.String(), nil
+}
+
+func (i Indent) Apply(in string) (string, error) {
+	out := strings.Builder{}
+
+	for line := range strings.Lines(in) {
+		if strings.TrimSpace(line) != "" {
+			out.WriteString(i.Prefix)
+		}
+
+		out.WriteString(line)
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		t    Transform
+		in   string
+		want string
+	}{
+		{
+			name: "replace",
+			t:    Replace{With: "b-$1", re: regexp.MustCompile(`a-(\w+)`)},
+			in:   "x a-1 a-2\n",
+			want: "x b-1 b-2\n",
+		},
+		{
+			name: "prepend",
+			t:    Prepend{Text: "# header\n"},
+			in:   "a\n",
+			want: "# header\na\n",
+		},
+		{
+			name: "append",
+			t:    Append{Text: "# footer\n"},
+			in:   "a\n",
+			want: "a\n# footer\n",
+		},
+		{
+			name: "strip",
+			t:    Strip{Begin: "BEGIN", End: "END"},
+			in:   "a\n# BEGIN\nb\nc\n# END\nd\n# BEGIN\ne\n# END",
+			want: "a\nd\n",
+		},
+		{
+			name: "strip nothing",
+			t:    Strip{Begin: "BEGIN", End: "END"},
+			in:   "a\nb\n",
+			want: "a\nb\n",
+		},
+		{
+			name: "indent",
+			t:    Indent{Prefix: "  "},
+			in:   "a\n\n  b\nc",
+			want: "  a\n\n    b\n  c",
+		},
+		{
+			name: "pipeline",
+			t: Transforms{
+				Prepend{Text: "a\n"},
+				Indent{Prefix: "- "},
+				Append{Text: "b\n"},
+			},
+			in:   "c\n",
+			want: "- a\n- c\nb\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.t.Apply(test.in)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+
+	t.Run("unterminated strip", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Transforms{Strip{Begin: "BEGIN", End: "END"}}.Apply("a\nBEGIN\nb\n")
+		if !errors.Is(err, ErrUnterminated) {
+			t.Fatalf("expected error %v, got %v", ErrUnterminated, err)
+		}
+
+		if !errors.Is(err, ErrFailTransform) {
+			t.Fatalf("expected error %v, got %v", ErrFailTransform, err)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+/*
+Package etag implements a client.Doer that caches the GET responses on disk,
+and revalidates them with conditional requests.
+
+A response that didn't change is answered with a 304, which doesn't count
+against the rate limit, and the cached body is used instead.
+https://docs.github.com/en/rest/using-the-rest-api/best-practices-for-using-the-rest-api?apiVersion=2022-11-28#use-conditional-requests-if-appropriate
+*/
+package etag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/nobe4/gh-ln/pkg/client"
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+type Client struct {
+	client client.Doer
+	dir    string
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// entry is a cached response.
+type entry struct {
+	ETag   string      `json:"etag"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+func New(c client.Doer, dir string) *Client {
+	return &Client{client: c, dir: dir}
+}
+
+// Stats returns the number of responses served from the cache, and the number
+// of responses fetched.
+func (c *Client) Stats() (int64, int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return c.client.Do(req) //nolint:wrapcheck // The cache is transparent.
+	}
+
+	path := c.path(req)
+
+	cached, cachedErr := read(path)
+	if cachedErr == nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // The cache is transparent.
+	}
+
+	if res.StatusCode == http.StatusNotModified && cachedErr == nil {
+		res.Body.Close()
+
+		c.hits.Add(1)
+		log.DebugContext(req.Context(), "HTTP cache hit", "url", req.URL)
+
+		return cached.response(req, res.Header), nil
+	}
+
+	c.misses.Add(1)
+	log.DebugContext(req.Context(), "HTTP cache miss", "url", req.URL, "status", res.StatusCode)
+
+	etag := res.Header.Get("ETag")
+	if res.StatusCode != http.StatusOK || etag == "" {
+		return res, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := write(path, entry{ETag: etag, Header: res.Header, Body: body}); err != nil {
+		log.DebugContext(req.Context(), "Failed to write the HTTP cache", "url", req.URL, "err", err)
+	}
+
+	return res, nil
+}
+
+// path returns the file caching the response of the request. The URL is
+// hashed, to be usable as a file name.
+func (c *Client) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String() + " " + req.Header.Get("Accept")))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// response rebuilds the cached response, with the current rate limit headers.
+func (e entry) response(req *http.Request, h http.Header) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	for k, v := range h {
+		if strings.HasPrefix(k, "X-Ratelimit-") {
+			header[k] = v
+		}
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(e.Body)),
+		Request:    req,
+	}
+}
+
+func read(path string) (entry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return entry{}, err //nolint:wrapcheck // A missing entry is expected.
+	}
+
+	e := entry{}
+	if err := json.Unmarshal(content, &e); err != nil {
+		return entry{}, fmt.Errorf("invalid cache entry %s: %w", path, err)
+	}
+
+	return e, nil
+}
+
+// write writes the entry through a temporary file, so concurrent readers never
+// see a partial entry.
+func write(path string, e entry) error {
+	content, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package etag
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	version := "1"
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(100-requests))
+
+		if r.Method != http.MethodGet {
+			fmt.Fprint(w, "posted")
+
+			return
+		}
+
+		etag := `"` + version + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Link", `<next>; rel="next"`)
+		fmt.Fprint(w, "version "+version)
+	}))
+	t.Cleanup(ts.Close)
+
+	// Each run has its own client, sharing the directory.
+	dir := t.TempDir()
+
+	get := func(method string) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(t.Context(), method, ts.URL+"/path", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := New(http.DefaultClient, dir).Do(req)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		return res
+	}
+
+	assert := func(res *http.Response, want, remaining string) {
+		t.Helper()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != http.StatusOK || string(body) != want {
+			t.Fatalf("want 200 %q, got %d %q", want, res.StatusCode, body)
+		}
+
+		if got := res.Header.Get("Link"); got != `<next>; rel="next"` {
+			t.Fatalf("want the Link header to be kept, got %q", got)
+		}
+
+		if got := res.Header.Get("X-RateLimit-Remaining"); got != remaining {
+			t.Fatalf("want the current rate limit %s, got %s", remaining, got)
+		}
+	}
+
+	assert(get(http.MethodGet), "version 1", "99")
+
+	// Served from the cache.
+	assert(get(http.MethodGet), "version 1", "98")
+
+	// Changed on the server.
+	version = "2"
+
+	assert(get(http.MethodGet), "version 2", "97")
+	assert(get(http.MethodGet), "version 2", "96")
+
+	if res := get(http.MethodPost); res.Header.Get("X-RateLimit-Remaining") != "95" {
+		t.Fatal("want POST requests to be sent")
+	}
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"etag"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	t.Cleanup(ts.Close)
+
+	c := New(http.DefaultClient, t.TempDir())
+
+	for range 3 {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		res.Body.Close()
+	}
+
+	if hits, misses := c.Stats(); hits != 2 || misses != 1 {
+		t.Fatalf("want 2 hits and 1 miss, got %d and %d", hits, misses)
+	}
+}
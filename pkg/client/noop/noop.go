@@ -34,7 +34,12 @@ func (c Client) noopPost(req *http.Request) (*http.Response, error) {
 		return c.fallback.Do(req)
 	}
 
-	log.Notice("[NOOP] HTTP", "method", req.Method, "path", req.URL.Path)
+	if regexp.MustCompile("/graphql$").MatchString(req.URL.Path) {
+		//nolint:wrapcheck // github.GetFiles only reads.
+		return c.fallback.Do(req)
+	}
+
+	log.NoticeContext(req.Context(), "[NOOP] HTTP", "method", req.Method, "path", req.URL.Path)
 
 	switch {
 	// github.CreateBranch
@@ -42,16 +47,61 @@ func (c Client) noopPost(req *http.Request) (*http.Response, error) {
 		regexp.MustCompile("/repos/[^/]+/[^/]+/git/refs").MatchString(req.URL.Path):
 		return response(http.StatusCreated, `{}`), nil
 
-	// github.UpdateFile
-	case req.Method == http.MethodPut &&
-		regexp.MustCompile("/repos/[^/]+/[^/]+/contents/.+").MatchString(req.URL.Path):
-		return response(http.StatusOK, `{"sha":"noop_sha_1234"}`), nil
+	// github.CreateBlob
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/blobs").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{"sha":"noop_blob"}`), nil
+
+	// github.CreateTree
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/trees").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{"sha":"noop_tree"}`), nil
+
+	// github.CreateCommit
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/commits").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{"sha":"noop_commit"}`), nil
+
+	// github.UpdateRef
+	case req.Method == http.MethodPatch &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/refs/.+").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{}`), nil
+
+	// github.DeleteBranch
+	case req.Method == http.MethodDelete &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/refs/.+").MatchString(req.URL.Path):
+		return response(http.StatusNoContent, ""), nil
+
+	// github.RequestReviewers
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/pulls/[^/]+/requested_reviewers").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{}`), nil
 
 	// github.CreatePull
 	case req.Method == http.MethodPost &&
 		regexp.MustCompile("/repos/[^/]+/[^/]+/pulls").MatchString(req.URL.Path):
 		return response(http.StatusOK, `{"number": -1}`), nil
 
+	// github.AddLabels, github.AddAssignees
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/issues/[^/]+/(labels|assignees)").MatchString(req.URL.Path):
+		return response(http.StatusOK, `[]`), nil
+
+	// github.AddComment
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/issues/[^/]+/comments").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{}`), nil
+
+	// github.UpdatePull, github.ClosePull
+	case req.Method == http.MethodPatch &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/pulls/[^/]+").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{}`), nil
+
+	// github.SetMilestone
+	case req.Method == http.MethodPatch &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/issues/[^/]+").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{}`), nil
+
 	default:
 		return response(http.StatusBadRequest, ""),
 			fmt.Errorf("%w: %s path %s", errors.ErrUnsupported, req.Method, req.URL.Path)
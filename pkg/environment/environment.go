@@ -27,6 +27,7 @@ const (
 	DefaultServer   = "https://github.com"
 	DefaultConfig   = ".ln-config.yaml"
 	DefaultRunID    = ""
+	CommandValidate = "validate"
 	Redacted        = "[redacted]"
 	Missing         = "[missing]"
 )
@@ -50,6 +51,21 @@ type Environment struct {
 	OnAction    bool        `json:"on_action"`
 	ExecURL     string      `json:"exec_url"`
 	Debug       bool        `json:"debug"` // RUNNER_DEBUG
+
+	// Concurrency is the number of groups processed, and links populated, at
+	// once.
+	Concurrency int `json:"concurrency"`
+
+	// CacheDir is where the API responses are cached across runs, if set.
+	CacheDir string `json:"cache_dir,omitempty"`
+
+	// GraphQL reads the files of the links in batches, with GraphQL.
+	GraphQL bool `json:"graphql"`
+
+	// Command is the subcommand to run instead of syncing, e.g. `validate`,
+	// with its arguments.
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
 }
 
 //nolint:revive // No, I don't want to leak secrets.
@@ -21,30 +21,30 @@ var (
 )
 
 func (g *GitHub) Auth(ctx context.Context, token, appID, appPrivateKey, appInstallID string) error {
-	log.Group("Authentication")
-	defer log.GroupEnd()
+	log.GroupContext(ctx, "Authentication")
+	defer log.GroupEndContext(ctx)
 
 	g.Token = token
 
 	if appID != "" && appPrivateKey != "" && appInstallID != "" {
-		log.Info("Using app authentication")
+		log.InfoContext(ctx, "Using app authentication")
 
 		var jwtToken string
 
 		jwtToken, err := jwt.New(time.Now().Unix(), appID, appPrivateKey)
 		if err != nil {
-			log.Error("Failed to create a JWT", "err", err)
+			log.ErrorContext(ctx, "Failed to create a JWT", "err", err)
 
 			return fmt.Errorf("%w: %w", errGetJWT, err)
 		}
 
 		if g.Token, err = g.GetAppToken(ctx, appInstallID, jwtToken); err != nil {
-			log.Error("Failed to get app token", "err", err)
+			log.ErrorContext(ctx, "Failed to get app token", "err", err)
 
 			return err
 		}
 	} else {
-		log.Info("Using token authentication")
+		log.InfoContext(ctx, "Using token authentication")
 	}
 
 	return nil
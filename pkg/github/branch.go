@@ -12,11 +12,13 @@ import (
 )
 
 var (
-	ErrNoBranch     = errors.New("branch not found")
-	ErrGetBranch    = errors.New("failed to get branch")
-	ErrCreateBranch = errors.New("failed to create branch")
-	ErrBranchExists = errors.New("branch already exist")
-	ErrDeleteBranch = errors.New("failed to delete branch")
+	ErrNoBranch      = errors.New("branch not found")
+	ErrGetBranch     = errors.New("failed to get branch")
+	ErrCreateBranch  = errors.New("failed to create branch")
+	ErrBranchExists  = errors.New("branch already exist")
+	ErrDeleteBranch  = errors.New("failed to delete branch")
+	ErrGetProtection = errors.New("failed to get branch protection")
+	ErrGetRules      = errors.New("failed to get branch rules")
 )
 
 type Commit struct {
@@ -24,14 +26,42 @@ type Commit struct {
 }
 
 type Branch struct {
-	Name   string `json:"name"`
-	Commit Commit `json:"commit"`
-	New    bool   `json:"new"`
+	Name      string `json:"name"`
+	Commit    Commit `json:"commit"`
+	New       bool   `json:"new"`
+	Protected bool   `json:"protected"`
+}
+
+// BranchProtection holds the protection rules that prevent pushing directly to
+// a branch. Their details are not needed.
+type BranchProtection struct {
+	RequiredPullRequestReviews *struct{} `json:"required_pull_request_reviews"`
+	RequiredStatusChecks       *struct{} `json:"required_status_checks"`
+	Restrictions               *struct{} `json:"restrictions"`
+	LockBranch                 *struct {
+		Enabled bool `json:"enabled"`
+	} `json:"lock_branch"`
+}
+
+// BranchRule is a ruleset rule that applies to a branch.
+type BranchRule struct {
+	Type string `json:"type"`
+}
+
+// blocksPush reports whether the rule prevents pushing directly to the branch.
+// https://docs.github.com/en/rest/repos/rules?apiVersion=2022-11-28#get-rules-for-a-branch
+func (r BranchRule) blocksPush() bool {
+	switch r.Type {
+	case "update", "pull_request", "required_status_checks", "required_deployments", "merge_queue":
+		return true
+	default:
+		return false
+	}
 }
 
 // https://docs.github.com/en/rest/branches/branches?apiVersion=2022-11-28#get-a-branch
 func (g *GitHub) GetBranch(ctx context.Context, r Repo, name string) (Branch, error) {
-	log.Debug("Get branch", "repo", r, "name", name)
+	log.DebugContext(ctx, "Get branch", "repo", r, "name", name)
 
 	b := Branch{}
 
@@ -52,7 +82,7 @@ func (g *GitHub) GetBranch(ctx context.Context, r Repo, name string) (Branch, er
 
 // https://docs.github.com/en/rest/git/refs?apiVersion=2022-11-28#create-a-reference
 func (g *GitHub) CreateBranch(ctx context.Context, r Repo, name, sha string) (Branch, error) {
-	log.Debug("Create branch", "repo", r, "name", name, "sha", sha)
+	log.DebugContext(ctx, "Create branch", "repo", r, "name", name, "sha", sha)
 
 	b := Branch{
 		Name: name,
@@ -110,3 +140,69 @@ func (g *GitHub) GetOrCreateBranch(ctx context.Context, r Repo, name, sha string
 
 	return g.CreateBranch(ctx, r, name, sha)
 }
+
+// CanPush reports whether commits can be pushed directly to the branch, i.e.
+// it is not protected by rules requiring a pull request, status checks or
+// specific actors. Both the branch protection and the rulesets are checked.
+// When the protection can't be read, which needs admin permissions, pushing is
+// assumed to be forbidden.
+// https://docs.github.com/en/rest/branches/branch-protection?apiVersion=2022-11-28#get-branch-protection
+func (g *GitHub) CanPush(ctx context.Context, r Repo, b Branch) (bool, error) {
+	if !b.Protected {
+		return true, nil
+	}
+
+	log.DebugContext(ctx, "Get branch protection", "repo", r, "branch", b.Name)
+
+	p := BranchProtection{}
+
+	path := fmt.Sprintf("/repos/%s/branches/%s/protection", r, b.Name)
+
+	if status, err := g.req(ctx, http.MethodGet, path, nil, &p); err != nil {
+		switch status {
+		case http.StatusNotFound:
+			// The branch may be protected by rulesets only.
+			return g.canPushRules(ctx, r, b)
+		case http.StatusForbidden:
+			log.WarnContext(ctx, "Cannot read branch protection, assuming pushing is not allowed", "repo", r, "branch", b.Name)
+
+			return false, nil
+		default:
+			return false, fmt.Errorf("%w: %w", ErrGetProtection, err)
+		}
+	}
+
+	locked := p.LockBranch != nil && p.LockBranch.Enabled
+
+	if p.RequiredPullRequestReviews != nil || p.RequiredStatusChecks != nil || p.Restrictions != nil || locked {
+		return false, nil
+	}
+
+	return g.canPushRules(ctx, r, b)
+}
+
+// canPushRules reports whether the rulesets applying to the branch allow
+// pushing directly to it. The bypass lists are not known, so any blocking rule
+// forbids pushing.
+// https://docs.github.com/en/rest/repos/rules?apiVersion=2022-11-28#get-rules-for-a-branch
+func (g *GitHub) canPushRules(ctx context.Context, r Repo, b Branch) (bool, error) {
+	log.DebugContext(ctx, "Get branch rules", "repo", r, "branch", b.Name)
+
+	rules := []BranchRule{}
+
+	path := fmt.Sprintf("/repos/%s/rules/branches/%s", r, b.Name)
+
+	if _, err := g.req(ctx, http.MethodGet, path, nil, &rules); err != nil {
+		return false, fmt.Errorf("%w: %w", ErrGetRules, err)
+	}
+
+	for _, rule := range rules {
+		if rule.blocksPush() {
+			log.DebugContext(ctx, "Branch rule blocks pushing", "repo", r, "branch", b.Name, "rule", rule.Type)
+
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -1,6 +1,7 @@
 package github
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,6 +13,7 @@ const (
 	sha           = "sha123"
 	branchAPIPath = "/repos/owner/repo/branches/branch"
 	refAPIPath    = "/repos/owner/repo/git/refs"
+	rulesAPIPath  = "/repos/owner/repo/rules/branches/branch"
 )
 
 func TestGetBranch(t *testing.T) {
@@ -220,3 +222,99 @@ func TestGetOrCreateBranch(t *testing.T) {
 		}
 	})
 }
+
+func TestCanPush(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unprotected branch", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(_ http.ResponseWriter, _ *http.Request) {
+			t.Fatal("no request expected")
+		})
+
+		got, err := g.CanPush(t.Context(), repo, Branch{Name: branch})
+		if err != nil || !got {
+			t.Fatalf("want true, got %v, %v", got, err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		rules  string
+		want   bool
+	}{
+		{name: "no protection", status: http.StatusNotFound, want: true},
+		{name: "no permission", status: http.StatusForbidden},
+		{name: "no blocking rule", status: http.StatusOK, body: `{"enforce_admins": {"enabled": true}}`, want: true},
+		{name: "requires reviews", status: http.StatusOK, body: `{"required_pull_request_reviews": {}}`},
+		{name: "requires checks", status: http.StatusOK, body: `{"required_status_checks": {"contexts": ["ci"]}}`},
+		{name: "restricts pushes", status: http.StatusOK, body: `{"restrictions": {"users": []}}`},
+		{name: "locked", status: http.StatusOK, body: `{"lock_branch": {"enabled": true}}`},
+		{name: "no blocking ruleset", status: http.StatusNotFound, rules: `[{"type": "deletion"}]`, want: true},
+		{name: "ruleset requires a pull", status: http.StatusNotFound, rules: `[{"type": "pull_request"}]`},
+		{name: "ruleset restricts updates", status: http.StatusOK, body: `{}`, rules: `[{"type": "update"}]`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == rulesAPIPath {
+					assertReq(t, r, http.MethodGet, rulesAPIPath, nil)
+					fmt.Fprintln(w, cmp.Or(test.rules, "[]"))
+
+					return
+				}
+
+				assertReq(t, r, http.MethodGet, branchAPIPath+"/protection", nil)
+				w.WriteHeader(test.status)
+				fmt.Fprintln(w, test.body)
+			})
+
+			got, err := g.CanPush(t.Context(), repo, Branch{Name: branch, Protected: true})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+
+	t.Run("server error", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		_, err := g.CanPush(t.Context(), repo, Branch{Name: branch, Protected: true})
+		if !errors.Is(err, ErrGetProtection) {
+			t.Fatalf("expected error %v, got %v", ErrGetProtection, err)
+		}
+	})
+
+	t.Run("rules server error", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == rulesAPIPath {
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := g.CanPush(t.Context(), repo, Branch{Name: branch, Protected: true})
+		if !errors.Is(err, ErrGetRules) {
+			t.Fatalf("expected error %v, got %v", ErrGetRules, err)
+		}
+	})
+}
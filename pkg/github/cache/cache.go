@@ -0,0 +1,216 @@
+/*
+Package cache implements a github.Getter that caches the results of another,
+so each resource is read once per run, even by concurrent callers.
+
+It is meant for resources that don't change during a run, like the sources of
+the links.
+*/
+package cache
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nobe4/gh-ln/pkg/github"
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+type Getter struct {
+	getter github.Getter
+
+	// Batch reads the files in Prefetch, it is optional.
+	Batch github.FilesGetter
+
+	mu      sync.Mutex
+	entries map[string]*entry
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// entry is the result of a call, available once done is closed.
+type entry struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+func New(g github.Getter) *Getter {
+	return &Getter{
+		getter:  g,
+		entries: map[string]*entry{},
+	}
+}
+
+// Stats returns the number of calls answered from the cache, and the number of
+// calls made to the wrapped getter.
+func (c *Getter) Stats() (int64, int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+func (c *Getter) LogStats(ctx context.Context) {
+	hits, misses := c.Stats()
+	log.DebugContext(ctx, "Cache stats", "hits", hits, "misses", misses)
+}
+
+// get returns the cached result for the key, or calls fetch and caches its
+// result. Concurrent calls for the same key wait for the first one.
+func get[T any](ctx context.Context, c *Getter, key string, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+
+	if !ok {
+		e = &entry{done: make(chan struct{})}
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.hits.Add(1)
+		log.DebugContext(ctx, "Cache hit", "key", key)
+
+		<-e.done
+
+		v, _ := e.value.(T)
+
+		return v, e.err
+	}
+
+	c.misses.Add(1)
+	log.DebugContext(ctx, "Cache miss", "key", key)
+
+	v, err := fetch()
+	e.value, e.err = v, err
+
+	close(e.done)
+
+	return v, err
+}
+
+func (c *Getter) GetFile(ctx context.Context, f *github.File) error {
+	got, err := get(ctx, c, "file:"+f.APIPath(), func() (github.File, error) {
+		got := github.File{Repo: f.Repo, Path: f.Path, Ref: f.Ref}
+		err := c.getter.GetFile(ctx, &got)
+
+		return got, err //nolint:wrapcheck // The cache is transparent.
+	})
+	if err != nil {
+		return err
+	}
+
+	// NOTE: Only the content from the API is set, like github.GetFile.
+	f.Name = got.Name
+	f.Path = got.Path
+	f.Content = got.Content
+	f.SHA = got.SHA
+	f.HTMLURL = got.HTMLURL
+	f.Binary = got.Binary
+
+	return nil
+}
+
+// Prefetch reads the files that aren't cached yet with Batch, all at once, so
+// the following GetFile calls are cache hits. It does nothing without Batch.
+func (c *Getter) Prefetch(ctx context.Context, files []github.File) {
+	if c.Batch == nil {
+		return
+	}
+
+	batch := []*github.File{}
+	pending := []*entry{}
+
+	c.mu.Lock()
+
+	for _, f := range files {
+		key := "file:" + f.APIPath()
+		if _, ok := c.entries[key]; ok {
+			continue
+		}
+
+		e := &entry{done: make(chan struct{})}
+		c.entries[key] = e
+
+		pending = append(pending, e)
+		batch = append(batch, &github.File{Repo: f.Repo, Path: f.Path, Ref: f.Ref})
+	}
+
+	c.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	c.misses.Add(int64(len(batch)))
+	log.DebugContext(ctx, "Prefetch files", "count", len(batch))
+
+	errs := c.Batch.GetFiles(ctx, batch)
+
+	for i, e := range pending {
+		e.value, e.err = *batch[i], errs[i]
+		close(e.done)
+	}
+}
+
+func (c *Getter) GetRepo(ctx context.Context, r *github.Repo) error {
+	got, err := get(ctx, c, "repo:"+r.APIPath(), func() (github.Repo, error) {
+		got := *r
+		err := c.getter.GetRepo(ctx, &got)
+
+		return got, err //nolint:wrapcheck // The cache is transparent.
+	})
+	if err != nil {
+		return err
+	}
+
+	*r = got
+
+	return nil
+}
+
+func (c *Getter) GetTree(ctx context.Context, r github.Repo, ref string) (github.Tree, error) {
+	t, err := get(ctx, c, "tree:"+r.APIPath()+"@"+ref, func() (github.Tree, error) {
+		return c.getter.GetTree(ctx, r, ref) //nolint:wrapcheck // The cache is transparent.
+	})
+
+	t.Entries = slices.Clone(t.Entries)
+
+	return t, err
+}
+
+func (c *Getter) GetBlob(ctx context.Context, r github.Repo, sha string) ([]byte, error) {
+	b, err := get(ctx, c, "blob:"+r.APIPath()+"@"+sha, func() ([]byte, error) {
+		return c.getter.GetBlob(ctx, r, sha) //nolint:wrapcheck // The cache is transparent.
+	})
+
+	return slices.Clone(b), err
+}
+
+func (c *Getter) ListRepos(ctx context.Context, owner string) ([]github.RepoInfo, error) {
+	repos, err := get(ctx, c, "repos:"+owner, func() ([]github.RepoInfo, error) {
+		return c.getter.ListRepos(ctx, owner) //nolint:wrapcheck // The cache is transparent.
+	})
+
+	return slices.Clone(repos), err
+}
+
+func (c *Getter) ListTags(ctx context.Context, r github.Repo) ([]github.Tag, error) {
+	tags, err := get(ctx, c, "tags:"+r.APIPath(), func() ([]github.Tag, error) {
+		return c.getter.ListTags(ctx, r) //nolint:wrapcheck // The cache is transparent.
+	})
+
+	return slices.Clone(tags), err
+}
+
+func (c *Getter) GetLatestRelease(ctx context.Context, r github.Repo) (github.Release, error) {
+	return get(ctx, c, "release:"+r.APIPath(), func() (github.Release, error) {
+		return c.getter.GetLatestRelease(ctx, r) //nolint:wrapcheck // The cache is transparent.
+	})
+}
+
+func (c *Getter) GetCommit(ctx context.Context, r github.Repo, ref string) (github.Commit, error) {
+	return get(ctx, c, "commit:"+r.APIPath()+"@"+ref, func() (github.Commit, error) {
+		return c.getter.GetCommit(ctx, r, ref) //nolint:wrapcheck // The cache is transparent.
+	})
+}
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nobe4/gh-ln/pkg/github"
+	gmock "github.com/nobe4/gh-ln/pkg/github/mock"
+)
+
+var errTest = errors.New("test")
+
+func TestGetFile(t *testing.T) {
+	t.Parallel()
+
+	calls := atomic.Int32{}
+	c := New(gmock.Getter{
+		FileHandler: func(f *github.File) error {
+			calls.Add(1)
+
+			if f.Path == "missing" {
+				return errTest
+			}
+
+			f.Content = "content@" + f.Ref
+			f.SHA = "sha"
+
+			return nil
+		},
+	})
+
+	repo := github.Repo{Owner: github.User{Login: "owner"}, Repo: "repo"}
+
+	wg := sync.WaitGroup{}
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			f := &github.File{Repo: repo, Path: "path", Ref: "main", Commit: "commit"}
+			if err := c.GetFile(t.Context(), f); err != nil {
+				t.Errorf("want no error, got %v", err)
+			}
+
+			if f.Content != "content@main" || f.SHA != "sha" || f.Commit != "commit" {
+				t.Errorf("want the file to be populated, got %+v", f)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("want 1 call, got %d", n)
+	}
+
+	if err := c.GetFile(t.Context(), &github.File{Repo: repo, Path: "path", Ref: "other"}); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	for range 2 {
+		if err := c.GetFile(t.Context(), &github.File{Repo: repo, Path: "missing"}); !errors.Is(err, errTest) {
+			t.Fatalf("want error %v, got %v", errTest, err)
+		}
+	}
+
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("want 3 calls, got %d", n)
+	}
+
+	if hits, misses := c.Stats(); hits != 10 || misses != 3 {
+		t.Fatalf("want 10 hits and 3 misses, got %d and %d", hits, misses)
+	}
+}
+
+func TestGetRepo(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	c := New(gmock.Getter{
+		RepoHandler: func(r *github.Repo) error {
+			calls++
+			r.DefaultBranch = "main"
+
+			return nil
+		},
+	})
+
+	for range 2 {
+		r := github.Repo{Owner: github.User{Login: "owner"}, Repo: "repo"}
+		if err := c.GetRepo(t.Context(), &r); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if r.DefaultBranch != "main" {
+			t.Fatalf("want default branch 'main', got %q", r.DefaultBranch)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("want 1 call, got %d", calls)
+	}
+}
+
+func TestListTags(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	c := New(gmock.Getter{
+		TagsHandler: func(github.Repo) ([]github.Tag, error) {
+			calls++
+
+			return []github.Tag{{Name: "v1.0.0"}}, nil
+		},
+	})
+
+	tags, err := c.ListTags(t.Context(), github.Repo{})
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	// Changing the result doesn't change the cache.
+	tags[0].Name = "changed"
+
+	tags, err = c.ListTags(t.Context(), github.Repo{})
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	if tags[0].Name != "v1.0.0" {
+		t.Fatalf("want tag 'v1.0.0', got %q", tags[0].Name)
+	}
+
+	if calls != 1 {
+		t.Fatalf("want 1 call, got %d", calls)
+	}
+}
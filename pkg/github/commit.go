@@ -0,0 +1,27 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+var ErrGetCommit = errors.New("failed to get commit")
+
+// GetCommit gets the commit a ref points to.
+// https://docs.github.com/en/rest/commits/commits?apiVersion=2022-11-28#get-a-commit
+func (g *GitHub) GetCommit(ctx context.Context, r Repo, ref string) (Commit, error) {
+	log.DebugContext(ctx, "Get commit", "repo", r, "ref", ref)
+
+	c := Commit{}
+
+	if _, err := g.req(ctx, http.MethodGet, r.APIPath()+"/commits/"+url.PathEscape(ref), nil, &c); err != nil {
+		return Commit{}, fmt.Errorf("%w %s@%s: %w", ErrGetCommit, r, ref, err)
+	}
+
+	return c, nil
+}
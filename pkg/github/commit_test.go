@@ -0,0 +1,43 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetCommit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("gets the commit", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, "/repos/owner/repo/commits/v1.2.3", nil)
+			fmt.Fprintln(w, `{"sha": "abc"}`)
+		})
+
+		got, err := g.GetCommit(t.Context(), repo, "v1.2.3")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got.SHA != "abc" {
+			t.Fatalf("want sha abc, got %q", got.SHA)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := g.GetCommit(t.Context(), repo, "main")
+		if !errors.Is(err, ErrGetCommit) {
+			t.Fatalf("want error %v, got %v", ErrGetCommit, err)
+		}
+	})
+}
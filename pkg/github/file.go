@@ -3,13 +3,20 @@ package github
 import (
 	"bytes"
 	"context"
+	"crypto/sha1" //nolint:gosec // Git identifies the blobs by their SHA-1.
 	"encoding/base64"
-	"encoding/json"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
+
+	"github.com/nobe4/gh-ln/pkg/log"
 )
 
+// binarySniffSize is how much of the content is checked by IsBinary, like Git
+// does.
+const binarySniffSize = 8000
+
 type File struct {
 	// Content from the API
 	Name    string `json:"name"`
@@ -18,6 +25,13 @@ type File struct {
 	SHA     string `json:"sha"` // Blob hash.
 	HTMLURL string `json:"html_url"`
 
+	// Binary is set when the content is not text, see IsBinary.
+	Binary bool `json:"binary"`
+
+	// Mode is the file's mode in the tree, e.g. ModeExecutable. It is not set
+	// by GetFile.
+	Mode string `json:"mode"`
+
 	// Content from the config
 	Repo   Repo   `json:"repo"`
 	Ref    string `json:"ref"`
@@ -27,8 +41,9 @@ type File struct {
 var (
 	ErrGetFile     = errors.New("failed to get file")
 	ErrMissingFile = errors.New("file does not exist")
-	ErrUpdateFile  = errors.New("failed to create/update file")
 	ErrDecodeFile  = errors.New("failed to decode file")
+	ErrGetBlob     = errors.New("failed to get blob")
+	ErrBlobSHA     = errors.New("content does not match the blob SHA")
 )
 
 func (f File) String() string {
@@ -47,13 +62,37 @@ func (f File) HTMLPath() string {
 	return fmt.Sprintf("/%s/blob/%s/%s", f.Repo, f.Commit, f.Path)
 }
 
+// BlobSHA returns the SHA that Git gives to a blob with the content.
+func BlobSHA(content []byte) string {
+	h := sha1.New() //nolint:gosec // Git identifies the blobs by their SHA-1.
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// IsBinary reports whether the content is binary, i.e. it has a NUL byte in its
+// beginning, with the same heuristic as Git.
+func IsBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), binarySniffSize)], 0) >= 0
+}
+
+// GetFile gets the file's content, as is. Binary files are marked as such.
+// Files over 1 MB are read from the Git blobs API, since the contents API
+// doesn't return their content.
 // https://docs.github.com/en/rest/repos/contents?apiVersion=2022-11-28#get-repository-content
 func (g *GitHub) GetFile(ctx context.Context, f *File) error {
+	out := struct {
+		*File
+
+		Encoding string `json:"encoding"`
+	}{File: f}
+
 	status, err := g.req(ctx,
 		http.MethodGet,
 		f.APIPath(),
 		nil,
-		&f,
+		&out,
 	)
 	if err != nil {
 		if status == http.StatusNotFound {
@@ -63,52 +102,61 @@ func (g *GitHub) GetFile(ctx context.Context, f *File) error {
 		return fmt.Errorf("%w: %w", ErrGetFile, err)
 	}
 
+	if out.Encoding == "none" && f.Content == "" {
+		log.DebugContext(ctx, "File is too large, reading its blob", "file", f, "sha", f.SHA)
+
+		return g.getLargeFile(ctx, f)
+	}
+
 	decoded, err := base64.StdEncoding.DecodeString(f.Content)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrDecodeFile, err)
 	}
 
 	f.Content = string(decoded)
+	f.Binary = IsBinary(decoded)
 
 	return nil
 }
 
-// https://docs.github.com/en/rest/repos/contents?apiVersion=2022-11-28#create-or-update-file-contents
-func (g *GitHub) UpdateFile(ctx context.Context, f File, branch, message string) (File, error) {
-	body, err := json.Marshal(struct {
-		Message string `json:"message"`
-		Content string `json:"content"`
-		SHA     string `json:"sha"`
-		Branch  string `json:"branch"`
-	}{
-		Message: message,
-		Content: base64.StdEncoding.EncodeToString([]byte(f.Content)),
-		Branch:  branch,
-		SHA:     f.SHA,
-	})
+// getLargeFile reads the file's content from its blob. The content is checked
+// against the file's SHA, so a truncated or empty content is never used in
+// place of the real one.
+func (g *GitHub) getLargeFile(ctx context.Context, f *File) error {
+	content, err := g.GetBlob(ctx, f.Repo, f.SHA)
 	if err != nil {
-		return File{}, fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+		return fmt.Errorf("%w %s: %w", ErrGetFile, f, err)
+	}
+
+	if sha := BlobSHA(content); sha != f.SHA {
+		return fmt.Errorf("%w %s: got %s, want %s", ErrBlobSHA, f, sha, f.SHA)
 	}
 
-	// NOTE: Non-trivial update.
-	// The response for this call will update the parameter file directly. It's
-	// fine because we want the new `SHA`. The `Name`, and `Path` won't change
-	// because we only update the content of the file. Also, since we're passing
-	// a file value (not pointer), the original file won't get changed; we are
-	// creating a new file. See tests.
+	f.Content = string(content)
+	f.Binary = IsBinary(content)
+
+	return nil
+}
+
+// https://docs.github.com/en/rest/git/blobs?apiVersion=2022-11-28#get-a-blob
+func (g *GitHub) GetBlob(ctx context.Context, r Repo, sha string) ([]byte, error) {
 	out := struct {
-		File File `json:"content"`
-	}{File: f}
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}{}
 
-	if _, err := g.req(
-		ctx,
-		http.MethodPut,
-		f.APIPath(),
-		bytes.NewReader(body),
-		&out,
-	); err != nil {
-		return File{}, fmt.Errorf("%w: %w", ErrUpdateFile, err)
+	if _, err := g.req(ctx, http.MethodGet, r.APIPath()+"/git/blobs/"+sha, nil, &out); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrGetBlob, sha, err)
+	}
+
+	if out.Encoding != "base64" {
+		return []byte(out.Content), nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(out.Content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecodeFile, err)
 	}
 
-	return out.File, nil
+	return decoded, nil
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -13,9 +14,42 @@ const (
 	contentPath   = "/repos/owner/repo/contents/" + filePath
 	content       = "ok"
 	base64Content = "b2s="
-	message       = "message"
+	contentSHA    = "b5754e20373fdaa5331ef6e4623dbae636225e3b" // git hash-object of "ok"
 )
 
+func TestBlobSHA(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":   "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391",
+		"ok": contentSHA,
+	}
+
+	for content, want := range tests {
+		if got := BlobSHA([]byte(content)); got != want {
+			t.Errorf("want %q for %q, got %q", want, content, got)
+		}
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		"":                      false,
+		"text\n":                false,
+		"caf\xc3\xa9":           false,
+		"\x89PNG\r\n\x1a\n\x00": true,
+		strings.Repeat("a", binarySniffSize) + "\x00": false,
+	}
+
+	for content, want := range tests {
+		if got := IsBinary([]byte(content)); got != want {
+			t.Errorf("want %v for %q, got %v", want, content, got)
+		}
+	}
+}
+
 func TestGetFile(t *testing.T) {
 	t.Parallel()
 
@@ -74,64 +108,93 @@ func TestGetFile(t *testing.T) {
 			t.Fatalf("expected content to be 'ok' but got %s", f.Content)
 		}
 	})
-}
-
-func TestUpdateFile(t *testing.T) {
-	t.Parallel()
 
-	f := File{
-		Repo:    repo,
-		Content: content,
-		SHA:     sha,
-		Path:    filePath,
-		Ref:     branch,
-	}
-
-	t.Run("fails", func(t *testing.T) {
+	t.Run("marks a binary file", func(t *testing.T) {
 		t.Parallel()
 
 		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"content": "AAH/"}`)
 		})
 
-		_, err := g.UpdateFile(t.Context(), f, branch, message)
-		if err == nil {
-			t.Fatal("expected error, got nil")
+		f := File{Repo: repo, Path: filePath, Ref: branch}
+
+		if err := g.GetFile(t.Context(), &f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if f.Content != "\x00\x01\xff" || !f.Binary {
+			t.Fatalf("expected binary content, got %q (binary: %v)", f.Content, f.Binary)
 		}
 	})
 
-	t.Run("succeeds", func(t *testing.T) {
+	large := func(t *testing.T, blobSHA string) func(w http.ResponseWriter, r *http.Request) {
+		t.Helper()
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case contentPath:
+				fmt.Fprintf(w, `{"content": "", "encoding": "none", "sha": "%s"}`, blobSHA)
+
+			case "/repos/owner/repo/git/blobs/" + blobSHA:
+				fmt.Fprintf(w, `{"content": "%s", "encoding": "base64"}`, base64Content)
+
+			default:
+				t.Fatalf("unexpected request %s", r.URL.Path)
+			}
+		}
+	}
+
+	t.Run("reads a large file from its blob", func(t *testing.T) {
 		t.Parallel()
 
-		const newSha = "newSha"
+		g := setup(t, large(t, contentSHA))
 
-		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
-			assertReq(t, r,
-				http.MethodPut,
-				contentPath,
-				fmt.Appendf(nil, `{"message":"%s","content":"%s","sha":"%s","branch":"%s"}`, message, base64Content, sha, branch),
-			)
-
-			w.WriteHeader(http.StatusCreated)
-			fmt.Fprintf(w, `{"content": {"sha":"%s"}}`, newSha)
-		})
+		f := File{Repo: repo, Path: filePath, Ref: branch}
 
-		c, err := g.UpdateFile(t.Context(), f, branch, message)
-		if err != nil {
+		if err := g.GetFile(t.Context(), &f); err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 
-		if c.SHA != newSha {
-			t.Fatalf("expected new sha to be '%s' but got '%s'", newSha, c.SHA)
+		if f.Content != content || f.SHA != contentSHA {
+			t.Fatalf("expected content %q with sha %q, got %q with %q", content, contentSHA, f.Content, f.SHA)
 		}
+	})
+
+	t.Run("fails when the blob doesn't match its SHA", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, large(t, sha))
 
-		if c.Content != f.Content {
-			t.Fatalf("expected content to be '%s' but got '%s'", f.Content, c.Content)
+		f := File{Repo: repo, Path: filePath, Ref: branch}
+
+		err := g.GetFile(t.Context(), &f)
+		if !errors.Is(err, ErrBlobSHA) {
+			t.Fatalf("expected error %v, got %v", ErrBlobSHA, err)
 		}
 
-		// Ensures that the old file stays unchanged.
-		if c.SHA == f.SHA {
-			t.Fatal("expected the original file not to change, but it did")
+		if f.Content != "" {
+			t.Fatalf("expected no content, got %q", f.Content)
+		}
+	})
+
+	t.Run("fails to get the blob", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != contentPath {
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+
+			fmt.Fprintf(w, `{"content": "", "encoding": "none", "sha": "%s"}`, contentSHA)
+		})
+
+		f := File{Repo: repo, Path: filePath, Ref: branch}
+
+		err := g.GetFile(t.Context(), &f)
+		if !errors.Is(err, ErrGetBlob) {
+			t.Fatalf("expected error %v, got %v", ErrGetBlob, err)
 		}
 	})
 }
@@ -0,0 +1,180 @@
+package github
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+// The modes of the files in a tree.
+const (
+	ModeFile       = "100644"
+	ModeExecutable = "100755"
+	ModeSymlink    = "120000"
+)
+
+var (
+	ErrNoChange     = errors.New("no change to commit")
+	ErrCreateBlob   = errors.New("failed to create blob")
+	ErrCreateCommit = errors.New("failed to create commit")
+	ErrUpdateRef    = errors.New("failed to update ref")
+)
+
+// Change is a change to a file, to be committed with CommitChanges.
+type Change struct {
+	Path    string
+	Content string
+
+	// Mode is the file's mode, ModeFile by default. The content of a symlink
+	// is the path it points to.
+	Mode string
+
+	// Delete removes the file instead of writing it.
+	Delete bool
+}
+
+// GitCommit is a commit of the Git database.
+type GitCommit struct {
+	SHA  string `json:"sha"`
+	Tree struct {
+		SHA string `json:"sha"`
+	} `json:"tree"`
+}
+
+// CommitChanges creates a single commit with all the changes on top of the
+// branch, and moves the branch to it. Either all the changes are committed, or
+// none.
+// The branch is not force-updated, so it fails if the branch moved in between.
+func (g *GitHub) CommitChanges(
+	ctx context.Context,
+	r Repo,
+	b Branch,
+	message string,
+	changes []Change,
+) (Commit, error) {
+	if len(changes) == 0 {
+		return Commit{}, ErrNoChange
+	}
+
+	log.DebugContext(ctx, "Commit changes", "repo", r, "branch", b.Name, "changes", len(changes))
+
+	parent, err := g.GetGitCommit(ctx, r, b.Commit.SHA)
+	if err != nil {
+		return Commit{}, err
+	}
+
+	entries := make([]NewTreeEntry, 0, len(changes))
+
+	for _, c := range changes {
+		e := NewTreeEntry{
+			Path: strings.TrimPrefix(c.Path, "/"),
+			Mode: cmp.Or(c.Mode, ModeFile),
+			Type: TreeEntryBlob,
+		}
+
+		if !c.Delete {
+			sha, err := g.CreateBlob(ctx, r, []byte(c.Content))
+			if err != nil {
+				return Commit{}, err
+			}
+
+			e.SHA = &sha
+		}
+
+		entries = append(entries, e)
+	}
+
+	tree, err := g.CreateTree(ctx, r, parent.Tree.SHA, entries)
+	if err != nil {
+		return Commit{}, err
+	}
+
+	commit, err := g.CreateCommit(ctx, r, message, tree, []string{parent.SHA})
+	if err != nil {
+		return Commit{}, err
+	}
+
+	if err := g.UpdateRef(ctx, r, "heads/"+b.Name, commit.SHA); err != nil {
+		return Commit{}, err
+	}
+
+	return Commit{SHA: commit.SHA}, nil
+}
+
+// https://docs.github.com/en/rest/git/commits?apiVersion=2022-11-28#get-a-commit-object
+func (g *GitHub) GetGitCommit(ctx context.Context, r Repo, sha string) (GitCommit, error) {
+	c := GitCommit{}
+
+	if _, err := g.req(ctx, http.MethodGet, r.APIPath()+"/git/commits/"+sha, nil, &c); err != nil {
+		return GitCommit{}, fmt.Errorf("%w %s@%s: %w", ErrGetCommit, r, sha, err)
+	}
+
+	return c, nil
+}
+
+// CreateBlob creates a blob with the content, encoded in base64 so any content
+// can be sent, and returns its SHA.
+// https://docs.github.com/en/rest/git/blobs?apiVersion=2022-11-28#create-a-blob
+func (g *GitHub) CreateBlob(ctx context.Context, r Repo, content []byte) (string, error) {
+	out := struct {
+		SHA string `json:"sha"`
+	}{}
+
+	if err := g.post(ctx, r.APIPath()+"/git/blobs", struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}{
+		Content:  base64.StdEncoding.EncodeToString(content),
+		Encoding: "base64",
+	}, &out); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrCreateBlob, err)
+	}
+
+	return out.SHA, nil
+}
+
+// https://docs.github.com/en/rest/git/commits?apiVersion=2022-11-28#create-a-commit
+func (g *GitHub) CreateCommit(ctx context.Context, r Repo, message, tree string, parents []string) (GitCommit, error) {
+	c := GitCommit{}
+
+	if err := g.post(ctx, r.APIPath()+"/git/commits", struct {
+		Message string   `json:"message"`
+		Tree    string   `json:"tree"`
+		Parents []string `json:"parents"`
+	}{
+		Message: message,
+		Tree:    tree,
+		Parents: parents,
+	}, &c); err != nil {
+		return GitCommit{}, fmt.Errorf("%w: %w", ErrCreateCommit, err)
+	}
+
+	return c, nil
+}
+
+// UpdateRef moves the ref, e.g. `heads/main`, to the commit. It is not forced.
+// https://docs.github.com/en/rest/git/refs?apiVersion=2022-11-28#update-a-reference
+func (g *GitHub) UpdateRef(ctx context.Context, r Repo, ref, sha string) error {
+	body, err := json.Marshal(struct {
+		SHA string `json:"sha"`
+	}{SHA: sha})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := r.APIPath() + "/git/refs/" + ref
+
+	if _, err := g.req(ctx, http.MethodPatch, path, bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("%w %s: %w", ErrUpdateRef, ref, err)
+	}
+
+	return nil
+}
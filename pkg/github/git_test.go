@@ -0,0 +1,149 @@
+package github
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCommitChanges(t *testing.T) {
+	t.Parallel()
+
+	b := Branch{Name: "main", Commit: Commit{SHA: "parent"}}
+	changes := []Change{
+		{Path: "/a.txt", Content: "a"},
+		{Path: "b.txt", Delete: true},
+		{Path: "c.sh", Content: "a", Mode: ModeExecutable},
+	}
+
+	handler := func(t *testing.T, fail string) func(w http.ResponseWriter, r *http.Request) {
+		t.Helper()
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			step := r.Method + " " + r.URL.Path
+			if step == fail {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+
+				return
+			}
+
+			switch step {
+			case "GET /repos/owner/repo/git/commits/parent":
+				fmt.Fprintln(w, `{"sha": "parent", "tree": {"sha": "base_tree"}}`)
+
+			case "POST /repos/owner/repo/git/blobs":
+				assertReq(t, r, http.MethodPost, r.URL.Path, []byte(`{"content":"YQ==","encoding":"base64"}`))
+				fmt.Fprintln(w, `{"sha": "blob_a"}`)
+
+			case "POST /repos/owner/repo/git/trees":
+				assertReq(t, r, http.MethodPost, r.URL.Path, []byte(`{"base_tree":"base_tree","tree":[`+
+					`{"path":"a.txt","mode":"100644","type":"blob","sha":"blob_a"},`+
+					`{"path":"b.txt","mode":"100644","type":"blob","sha":null},`+
+					`{"path":"c.sh","mode":"100755","type":"blob","sha":"blob_a"}]}`))
+				fmt.Fprintln(w, `{"sha": "tree"}`)
+
+			case "POST /repos/owner/repo/git/commits":
+				assertReq(t, r, http.MethodPost, r.URL.Path, []byte(`{"message":"msg","tree":"tree","parents":["parent"]}`))
+				fmt.Fprintln(w, `{"sha": "commit"}`)
+
+			case "PATCH /repos/owner/repo/git/refs/heads/main":
+				assertReq(t, r, http.MethodPatch, r.URL.Path, []byte(`{"sha":"commit"}`))
+				fmt.Fprintln(w, `{}`)
+
+			default:
+				t.Fatalf("unexpected request %s", step)
+			}
+		}
+	}
+
+	t.Run("commits the changes", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, handler(t, ""))
+
+		got, err := g.CommitChanges(t.Context(), repo, b, "msg", changes)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got.SHA != "commit" {
+			t.Fatalf("want commit sha, got %q", got.SHA)
+		}
+	})
+
+	t.Run("fails without changes", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, handler(t, ""))
+
+		_, err := g.CommitChanges(t.Context(), repo, b, "msg", nil)
+		if !errors.Is(err, ErrNoChange) {
+			t.Fatalf("want error %v, got %v", ErrNoChange, err)
+		}
+	})
+
+	tests := []struct {
+		fail string
+		want error
+	}{
+		{fail: "GET /repos/owner/repo/git/commits/parent", want: ErrGetCommit},
+		{fail: "POST /repos/owner/repo/git/blobs", want: ErrCreateBlob},
+		{fail: "POST /repos/owner/repo/git/trees", want: ErrCreateTree},
+		{fail: "POST /repos/owner/repo/git/commits", want: ErrCreateCommit},
+		{fail: "PATCH /repos/owner/repo/git/refs/heads/main", want: ErrUpdateRef},
+	}
+
+	for _, test := range tests {
+		t.Run("fails on "+strings.ToLower(test.fail), func(t *testing.T) {
+			t.Parallel()
+
+			g := setup(t, handler(t, test.fail))
+
+			_, err := g.CommitChanges(t.Context(), repo, b, "msg", changes)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("want error %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestCreateBlob(t *testing.T) {
+	t.Parallel()
+
+	// Over the 1 MB the contents API accepts, and not valid UTF-8.
+	content := bytes.Repeat([]byte("a\x00\xff"), 1<<20)
+
+	g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		assertReq(t, r, http.MethodPost, "/repos/owner/repo/git/blobs", nil)
+
+		body := struct {
+			Content  string `json:"content"`
+			Encoding string `json:"encoding"`
+		}{}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+
+		got, err := base64.StdEncoding.DecodeString(body.Content)
+		if err != nil || body.Encoding != "base64" || !bytes.Equal(got, content) {
+			t.Fatalf("want the whole content encoded in base64, got %d bytes in %q: %v", len(got), body.Encoding, err)
+		}
+
+		fmt.Fprintln(w, `{"sha": "blob"}`)
+	})
+
+	got, err := g.CreateBlob(t.Context(), repo, content)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got != "blob" {
+		t.Fatalf("want blob sha, got %q", got)
+	}
+}
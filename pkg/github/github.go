@@ -7,12 +7,15 @@ Refs:
 package github
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/nobe4/gh-ln/pkg/client"
 	"github.com/nobe4/gh-ln/pkg/log"
@@ -21,10 +24,16 @@ import (
 type Getter interface {
 	GetFile(ctx context.Context, f *File) error
 	GetRepo(ctx context.Context, r *Repo) error
+	GetTree(ctx context.Context, r Repo, ref string) (Tree, error)
+	GetBlob(ctx context.Context, r Repo, sha string) ([]byte, error)
+	ListRepos(ctx context.Context, owner string) ([]RepoInfo, error)
+	ListTags(ctx context.Context, r Repo) ([]Tag, error)
+	GetLatestRelease(ctx context.Context, r Repo) (Release, error)
+	GetCommit(ctx context.Context, r Repo, ref string) (Commit, error)
 }
 
 type Updater interface {
-	UpdateFile(ctx context.Context, f File, head string, msg string) (File, error)
+	CommitChanges(ctx context.Context, r Repo, b Branch, message string, changes []Change) (Commit, error)
 }
 
 type GetterUpdater interface {
@@ -32,6 +41,11 @@ type GetterUpdater interface {
 	Updater
 }
 
+// FilesGetter gets many files at once.
+type FilesGetter interface {
+	GetFiles(ctx context.Context, files []*File) []error
+}
+
 var (
 	ErrRequestFailed  = errors.New("request failed")
 	ErrMarshalRequest = errors.New("failed to marshal request")
@@ -45,12 +59,22 @@ type GitHub struct {
 	client   client.Doer
 	Token    string
 	endpoint string
+
+	// retries is the number of times a failed request is retried, and sleep
+	// waits in between.
+	retries int
+	sleep   func(context.Context, time.Duration) error
+
+	mu        sync.Mutex
+	rateLimit RateLimit
 }
 
 func New(c client.Doer, endpoint string) *GitHub {
 	return &GitHub{
 		client:   c,
 		endpoint: endpoint,
+		retries:  maxRetries,
+		sleep:    sleep,
 	}
 }
 
@@ -58,40 +82,124 @@ type User struct {
 	Login string `json:"login"`
 }
 
+// req sends the request to the API path and decodes the response into out,
+// if any. Rate limited and failed requests are retried, see retryWait.
 func (g *GitHub) req(ctx context.Context, method, path string, body io.Reader, out any) (int, error) {
-	url := g.endpoint + path
+	status, _, err := g.request(ctx, method, g.endpoint+path, body, out)
 
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
-	if err != nil {
-		log.Debug("Request", "method", method, "url", url, "status", "failed to create", "err", err)
+	return status, err
+}
 
-		return http.StatusInternalServerError, fmt.Errorf("failed to create request: %w", err)
+// request is like req, for a full URL, and also returns the response headers.
+func (g *GitHub) request(
+	ctx context.Context,
+	method, url string,
+	body io.Reader,
+	out any,
+) (int, http.Header, error) {
+	// NOTE: The body is read once, to be sent again on retries.
+	var content []byte
+
+	if body != nil {
+		var err error
+		if content, err = io.ReadAll(body); err != nil {
+			return http.StatusInternalServerError, nil, fmt.Errorf("failed to read request body: %w", err)
+		}
 	}
 
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("Authorization", "Bearer "+g.Token)
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(content))
+		if err != nil {
+			log.DebugContext(ctx, "Request", "method", method, "url", url, "status", "failed to create", "err", err)
+
+			return http.StatusInternalServerError, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+
+		res, err := g.client.Do(req)
+		if err == nil {
+			g.updateRateLimit(res.Header)
+		}
+
+		wait, retry := retryWait(method, res, err, attempt)
+		if retry && wait > maxWait {
+			log.WarnContext(ctx, "Rate limit resets too late, not retrying", "method", method, "url", url, "wait", wait)
+
+			retry = false
+		}
+
+		if !retry || attempt >= g.retries {
+			return g.handle(ctx, req, res, err, out)
+		}
+
+		if res != nil {
+			res.Body.Close()
+		}
+
+		log.WarnContext(ctx, "Retrying request",
+			"method", method, "url", url, "status", status(res), "err", err, "wait", wait, "attempt", attempt+1)
+
+		if err := g.sleep(ctx, wait); err != nil {
+			return http.StatusInternalServerError, nil, fmt.Errorf("%w: %w", ErrRequestFailed, err)
+		}
+	}
+}
+
+// handle decodes the response of the last attempt into out, if any.
+func (g *GitHub) handle(
+	ctx context.Context,
+	req *http.Request,
+	res *http.Response,
+	err error,
+	out any,
+) (int, http.Header, error) {
+	method, url := req.Method, req.URL.String()
 
-	res, err := g.client.Do(req)
 	if err != nil {
-		log.Debug("Request", "method", method, "url", url, "err", err, "status", res.StatusCode)
+		log.DebugContext(ctx, "Request", "method", method, "url", url, "err", err)
 
-		return res.StatusCode, fmt.Errorf("%w: %w", ErrRequestFailed, err)
+		return http.StatusInternalServerError, nil, fmt.Errorf("%w: %w", ErrRequestFailed, err)
 	}
 	defer res.Body.Close()
 
-	log.Debug("HTTP", "method", method, "url", url, "status", res.StatusCode)
+	log.DebugContext(ctx, "HTTP", "method", method, "url", url, "status", res.StatusCode,
+		"remaining", g.RateLimit().Remaining)
 
 	code2XX := res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices
 	if !code2XX {
-		return res.StatusCode, fmt.Errorf("%w (%s %s): %s", ErrRequestFailed, method, url, res.Status)
+		return res.StatusCode, res.Header, fmt.Errorf("%w (%s %s): %s", ErrRequestFailed, method, url, res.Status)
 	}
 
 	if out != nil {
 		err := json.NewDecoder(res.Body).Decode(out)
 		if err != nil {
-			return http.StatusInternalServerError, fmt.Errorf("failed to decode response: %w", err)
+			return http.StatusInternalServerError, res.Header, fmt.Errorf("failed to decode response: %w", err)
 		}
 	}
 
-	return res.StatusCode, nil
+	return res.StatusCode, res.Header, nil
+}
+
+func status(res *http.Response) int {
+	if res == nil {
+		return 0
+	}
+
+	return res.StatusCode
+}
+
+// post sends the data as JSON and decodes the response into out.
+func (g *GitHub) post(ctx context.Context, path string, data, out any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	if _, err := g.req(ctx, http.MethodPost, path, bytes.NewReader(body), out); err != nil {
+		return err
+	}
+
+	return nil
 }
@@ -2,13 +2,17 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -18,6 +22,8 @@ const (
 //nolint:gochecknoglobals // This is used across GitHub tests.
 var repo = Repo{Owner: User{Login: "owner"}, Repo: "repo"}
 
+var errTest = errors.New("test")
+
 func assertReq(t *testing.T, r *http.Request, method, path string, body []byte) {
 	t.Helper()
 
@@ -50,6 +56,7 @@ func setup(t *testing.T, f func(w http.ResponseWriter, r *http.Request)) *GitHub
 	// with ts.
 	g := New(http.DefaultClient, ts.URL)
 	g.Token = token
+	g.sleep = noSleep
 
 	return g
 }
@@ -161,3 +168,240 @@ func TestReq(t *testing.T) {
 		}
 	})
 }
+
+func noSleep(context.Context, time.Duration) error { return nil }
+
+// doer is a fake client.Doer, returning the responses in order.
+type doer struct {
+	responses []*http.Response
+	requests  []*http.Request
+	bodies    []string
+}
+
+func (d *doer) Do(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Test helper.
+	}
+
+	d.requests = append(d.requests, req)
+	d.bodies = append(d.bodies, string(body))
+
+	res := d.responses[0]
+	d.responses = d.responses[1:]
+
+	if res == nil {
+		return nil, errTest
+	}
+
+	return res, nil
+}
+
+func response(status int, headers map[string]string) *http.Response {
+	h := http.Header{}
+	for k, v := range headers {
+		h.Set(k, v)
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     h,
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+	}
+}
+
+func TestReqRetry(t *testing.T) {
+	t.Parallel()
+
+	reset := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		method    string
+		responses []*http.Response
+		want      int
+		attempts  int
+		wait      time.Duration
+	}{
+		{
+			name:      "succeeds",
+			method:    http.MethodGet,
+			responses: []*http.Response{response(http.StatusOK, nil)},
+			want:      http.StatusOK,
+			attempts:  1,
+		},
+		{
+			name:   "retries a server error",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(http.StatusBadGateway, nil),
+				response(http.StatusOK, nil),
+			},
+			want:     http.StatusOK,
+			attempts: 2,
+			wait:     backoffBase,
+		},
+		{
+			name:   "retries a network error",
+			method: http.MethodDelete,
+			responses: []*http.Response{
+				nil,
+				response(http.StatusOK, nil),
+			},
+			want:     http.StatusOK,
+			attempts: 2,
+			wait:     backoffBase,
+		},
+		{
+			name:   "does not retry a non-idempotent request",
+			method: http.MethodPost,
+			responses: []*http.Response{
+				response(http.StatusInternalServerError, nil),
+			},
+			want:     http.StatusInternalServerError,
+			attempts: 1,
+		},
+		{
+			name:   "does not retry a permission error",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "10"}),
+			},
+			want:     http.StatusForbidden,
+			attempts: 1,
+		},
+		{
+			name:   "retries a secondary rate limit after Retry-After",
+			method: http.MethodPost,
+			responses: []*http.Response{
+				response(http.StatusForbidden, map[string]string{"Retry-After": "30"}),
+				response(http.StatusCreated, nil),
+			},
+			want:     http.StatusCreated,
+			attempts: 2,
+			wait:     30 * time.Second,
+		},
+		{
+			name:   "retries a rate limit after the reset",
+			method: http.MethodPatch,
+			responses: []*http.Response{
+				response(http.StatusTooManyRequests, map[string]string{
+					"X-RateLimit-Remaining": "0",
+					"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+				}),
+				response(http.StatusOK, nil),
+			},
+			want:     http.StatusOK,
+			attempts: 2,
+			wait:     time.Until(reset) - 2*time.Second,
+		},
+		{
+			name:   "does not wait for a late reset",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(http.StatusTooManyRequests, map[string]string{"Retry-After": "3600"}),
+			},
+			want:     http.StatusTooManyRequests,
+			attempts: 1,
+		},
+		{
+			name:   "gives up",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(http.StatusServiceUnavailable, nil),
+				response(http.StatusServiceUnavailable, nil),
+				response(http.StatusServiceUnavailable, nil),
+				response(http.StatusServiceUnavailable, nil),
+			},
+			want:     http.StatusServiceUnavailable,
+			attempts: maxRetries + 1,
+			wait:     backoffBase + 2*backoffBase + 4*backoffBase,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &doer{responses: test.responses}
+			g := New(d, "https://api.test")
+
+			waited := time.Duration(0)
+			g.sleep = func(_ context.Context, d time.Duration) error {
+				waited += d
+
+				return nil
+			}
+
+			status, _ := g.req(t.Context(), test.method, PathUser, strings.NewReader("body"), nil)
+			if status != test.want {
+				t.Fatalf("want status %d, got %d", test.want, status)
+			}
+
+			if len(d.requests) != test.attempts {
+				t.Fatalf("want %d attempts, got %d", test.attempts, len(d.requests))
+			}
+
+			for i, body := range d.bodies {
+				if body != "body" {
+					t.Fatalf("want body to be sent on attempt %d, got %q", i, body)
+				}
+			}
+
+			// The waits have up to 50% of jitter.
+			if waited < test.wait || waited > test.wait*3/2+time.Second {
+				t.Fatalf("want to wait about %s, got %s", test.wait, waited)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	d := &doer{responses: []*http.Response{
+		response(http.StatusOK, nil),
+		response(http.StatusOK, map[string]string{
+			"X-RateLimit-Limit":     "5000",
+			"X-RateLimit-Remaining": "4999",
+			"X-RateLimit-Reset":     "1700000000",
+		}),
+		response(http.StatusOK, nil),
+	}}
+	g := New(d, "https://api.test")
+
+	if _, err := g.req(t.Context(), http.MethodGet, PathUser, nil, nil); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	if rl := g.RateLimit(); rl != (RateLimit{}) {
+		t.Fatalf("want no rate limit, got %+v", rl)
+	}
+
+	for range 2 {
+		if _, err := g.req(t.Context(), http.MethodGet, PathUser, nil, nil); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	}
+
+	want := RateLimit{Limit: 5000, Remaining: 4999, Reset: time.Unix(1700000000, 0)}
+	if rl := g.RateLimit(); rl != want {
+		t.Fatalf("want rate limit %+v, got %+v", want, rl)
+	}
+}
+
+func TestRetryAbortsWithContext(t *testing.T) {
+	t.Parallel()
+
+	d := &doer{responses: []*http.Response{response(http.StatusBadGateway, nil)}}
+	g := New(d, "https://api.test")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := g.req(ctx, http.MethodGet, PathUser, nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want error %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+// filesPerQuery is the number of files fetched by a single GraphQL query.
+const filesPerQuery = 50
+
+type graphqlRepo struct {
+	URL    string `json:"url"`
+	Object *struct {
+		OID         string  `json:"oid"`
+		Text        *string `json:"text"`
+		IsBinary    bool    `json:"isBinary"`
+		IsTruncated bool    `json:"isTruncated"`
+	} `json:"object"`
+}
+
+type graphqlError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Path    []any  `json:"path"`
+}
+
+// GetFiles gets the files like GetFile, but batched in GraphQL queries, across
+// repositories. It returns the error for each file.
+// The binary and truncated files can't be read with GraphQL, they are read
+// with GetFile instead.
+// https://docs.github.com/en/graphql/reference/objects#blob
+func (g *GitHub) GetFiles(ctx context.Context, files []*File) []error {
+	errs := make([]error, 0, len(files))
+
+	for batch := range slices.Chunk(files, filesPerQuery) {
+		errs = append(errs, g.getFiles(ctx, batch)...)
+	}
+
+	return errs
+}
+
+func (g *GitHub) getFiles(ctx context.Context, files []*File) []error {
+	log.DebugContext(ctx, "Get files", "count", len(files))
+
+	errs := make([]error, len(files))
+
+	fail := func(err error) []error {
+		for i := range errs {
+			errs[i] = fmt.Errorf("%w %s: %w", ErrGetFile, files[i], err)
+		}
+
+		return errs
+	}
+
+	body, err := json.Marshal(filesQuery(files))
+	if err != nil {
+		return fail(fmt.Errorf("%w: %w", ErrMarshalRequest, err))
+	}
+
+	out := struct {
+		Data   map[string]*graphqlRepo `json:"data"`
+		Errors []graphqlError          `json:"errors"`
+	}{}
+
+	if _, _, err := g.request(ctx, http.MethodPost, g.graphqlURL(), bytes.NewReader(body), &out); err != nil {
+		return fail(err)
+	}
+
+	if out.Data == nil {
+		return fail(fmt.Errorf("%w: %v", ErrRequestFailed, out.Errors))
+	}
+
+	for i, f := range files {
+		alias := fmt.Sprintf("f%d", i)
+		r := out.Data[alias]
+
+		switch {
+		case r == nil && !notFound(out.Errors, alias):
+			errs[i] = fmt.Errorf("%w %s: %v", ErrGetFile, f, out.Errors)
+
+		case r == nil || r.Object == nil:
+			errs[i] = fmt.Errorf("%w: %s", ErrMissingFile, f)
+
+		case r.Object.Text == nil || r.Object.IsBinary || r.Object.IsTruncated:
+			log.DebugContext(ctx, "File can't be read with GraphQL", "file", f)
+
+			errs[i] = g.GetFile(ctx, f)
+
+		// NOTE: The text is decoded by GitHub, e.g. a file that is not UTF-8
+		// nor detected as binary is altered. It's only used as is.
+		case BlobSHA([]byte(*r.Object.Text)) != r.Object.OID:
+			log.DebugContext(ctx, "File text doesn't match its blob, reading it again", "file", f, "sha", r.Object.OID)
+
+			errs[i] = g.GetFile(ctx, f)
+
+		default:
+			f.Path = strings.TrimPrefix(f.Path, "/")
+			f.Name = path.Base(f.Path)
+			f.Content = *r.Object.Text
+			f.SHA = r.Object.OID
+			f.Binary = IsBinary([]byte(f.Content))
+			f.HTMLURL = fmt.Sprintf("%s/blob/%s/%s", r.URL, cmp.Or(f.Ref, "HEAD"), f.Path)
+		}
+	}
+
+	return errs
+}
+
+// filesQuery builds the query getting the files, aliased `f0`, `f1`, etc. The
+// values are passed as variables, so they don't need escaping.
+func filesQuery(files []*File) any {
+	params := make([]string, 0, len(files))
+	fields := make([]string, 0, len(files))
+	vars := map[string]string{}
+
+	for i, f := range files {
+		params = append(params, fmt.Sprintf("$o%[1]d: String!, $n%[1]d: String!, $e%[1]d: String!", i))
+		fields = append(fields, fmt.Sprintf("f%[1]d: repository(owner: $o%[1]d, name: $n%[1]d) { "+
+			"url object(expression: $e%[1]d) { ... on Blob { oid text isBinary isTruncated } } }", i))
+
+		vars[fmt.Sprintf("o%d", i)] = f.Repo.Owner.Login
+		vars[fmt.Sprintf("n%d", i)] = f.Repo.Repo
+		vars[fmt.Sprintf("e%d", i)] = cmp.Or(f.Ref, "HEAD") + ":" + strings.TrimPrefix(f.Path, "/")
+	}
+
+	return struct {
+		Query     string            `json:"query"`
+		Variables map[string]string `json:"variables"`
+	}{
+		Query:     fmt.Sprintf("query(%s) {\n%s\n}", strings.Join(params, ", "), strings.Join(fields, "\n")),
+		Variables: vars,
+	}
+}
+
+// notFound reports whether the alias is null because the repository doesn't
+// exist, or isn't visible.
+func notFound(errs []graphqlError, alias string) bool {
+	for _, e := range errs {
+		if len(e.Path) > 0 && e.Path[0] == alias {
+			return e.Type == "NOT_FOUND"
+		}
+	}
+
+	return false
+}
+
+// graphqlURL returns the GraphQL endpoint next to the REST one, e.g.
+// `https://api.github.com/graphql`, or `https://HOST/api/graphql` for GitHub
+// Enterprise Server.
+func (g *GitHub) graphqlURL() string {
+	return strings.TrimSuffix(g.endpoint, "/v3") + "/graphql"
+}
@@ -0,0 +1,187 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// graphqlHandler stands in for the GraphQL API, serving the files by
+// `owner/repo@expression`. Binary files, and the latin1 file whose text is
+// altered, are served by the REST API.
+func graphqlHandler(
+	t *testing.T,
+	queries *atomic.Int32,
+	files map[string]string,
+) func(http.ResponseWriter, *http.Request) {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/contents/binary":
+			fmt.Fprint(w, `{"content": "AAE=", "sha": "binary_sha"}`)
+
+			return
+
+		case "/repos/owner/repo/contents/latin1":
+			fmt.Fprintf(w, `{"content": "Y2Fm6Q==", "sha": "%s"}`, BlobSHA([]byte("caf\xe9")))
+
+			return
+		}
+
+		assertReq(t, r, http.MethodPost, "/graphql", nil)
+		queries.Add(1)
+
+		in := struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Fatal(err)
+		}
+
+		data := map[string]any{}
+		errs := []map[string]any{}
+
+		for i := 0; ; i++ {
+			owner, ok := in.Variables[fmt.Sprintf("o%d", i)]
+			if !ok {
+				break
+			}
+
+			alias := fmt.Sprintf("f%d", i)
+			if !strings.Contains(in.Query, alias+": repository(") {
+				t.Fatalf("want alias %s in the query, got %s", alias, in.Query)
+			}
+
+			repo := owner + "/" + in.Variables[fmt.Sprintf("n%d", i)]
+			if repo != "owner/repo" {
+				data[alias] = nil
+				errs = append(errs, map[string]any{"type": "NOT_FOUND", "path": []string{alias}})
+
+				continue
+			}
+
+			expression := in.Variables[fmt.Sprintf("e%d", i)]
+
+			var object any
+
+			switch content, ok := files[expression]; {
+			case expression == "main:binary":
+				object = map[string]any{"oid": "binary_sha", "text": nil, "isBinary": true}
+			case expression == "main:latin1":
+				object = map[string]any{"oid": BlobSHA([]byte("caf\xe9")), "text": "caf\ufffd"}
+			case ok:
+				object = map[string]any{"oid": BlobSHA([]byte(content)), "text": content}
+			}
+
+			data[alias] = map[string]any{"url": "https://github.com/" + repo, "object": object}
+		}
+
+		if err := json.NewEncoder(w).Encode(map[string]any{"data": data, "errors": errs}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("gets the files in batches", func(t *testing.T) {
+		t.Parallel()
+
+		queries := atomic.Int32{}
+		g := setup(t, graphqlHandler(t, &queries, map[string]string{
+			"main:a.txt":     "a",
+			"HEAD:dir/b.txt": "b",
+		}))
+
+		files := []*File{
+			{Repo: repo, Path: "a.txt", Ref: "main"},
+			{Repo: repo, Path: "/dir/b.txt"},
+			{Repo: repo, Path: "missing", Ref: "main"},
+			{Repo: Repo{Owner: User{Login: "owner"}, Repo: "private"}, Path: "a.txt", Ref: "main"},
+			{Repo: repo, Path: "binary", Ref: "main"},
+			{Repo: repo, Path: "latin1", Ref: "main"},
+		}
+
+		for range filesPerQuery {
+			files = append(files, &File{Repo: repo, Path: "a.txt", Ref: "main"})
+		}
+
+		errs := g.GetFiles(t.Context(), files)
+
+		if n := queries.Load(); n != 2 {
+			t.Fatalf("want 2 queries, got %d", n)
+		}
+
+		if len(errs) != len(files) {
+			t.Fatalf("want %d errors, got %d", len(files), len(errs))
+		}
+
+		want := File{
+			Repo: repo, Path: "a.txt", Ref: "main", Name: "a.txt",
+			Content: "a", SHA: BlobSHA([]byte("a")), HTMLURL: "https://github.com/owner/repo/blob/main/a.txt",
+		}
+		if errs[0] != nil || *files[0] != want {
+			t.Fatalf("want %+v, got %+v (%v)", want, *files[0], errs[0])
+		}
+
+		if errs[1] != nil || files[1].Content != "b" || files[1].Path != "dir/b.txt" || files[1].Name != "b.txt" {
+			t.Fatalf("want dir/b.txt to be read, got %+v (%v)", *files[1], errs[1])
+		}
+
+		for _, i := range []int{2, 3} {
+			if !errors.Is(errs[i], ErrMissingFile) {
+				t.Fatalf("want error %v for %s, got %v", ErrMissingFile, files[i], errs[i])
+			}
+		}
+
+		if errs[4] != nil || files[4].Content != "\x00\x01" || files[4].SHA != "binary_sha" {
+			t.Fatalf("want the binary file to be read with REST, got %+v (%v)", *files[4], errs[4])
+		}
+
+		if errs[5] != nil || files[5].Content != "caf\xe9" {
+			t.Fatalf("want the altered file to be read with REST, got %+v (%v)", *files[5], errs[5])
+		}
+
+		if last := files[len(files)-1]; errs[len(files)-1] != nil || last.Content != "a" {
+			t.Fatalf("want the last file to be read, got %+v (%v)", *last, errs[len(files)-1])
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"data": null, "errors": [{"message": "bad query"}]}`)
+		})
+
+		errs := g.GetFiles(t.Context(), []*File{{Repo: repo, Path: "a"}, {Repo: repo, Path: "b"}})
+
+		for _, err := range errs {
+			if !errors.Is(err, ErrGetFile) {
+				t.Fatalf("want error %v, got %v", ErrGetFile, err)
+			}
+		}
+	})
+}
+
+func TestGraphqlURL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"https://api.github.com":          "https://api.github.com/graphql",
+		"https://ghes.example.com/api/v3": "https://ghes.example.com/api/graphql",
+	}
+
+	for endpoint, want := range tests {
+		if got := New(nil, endpoint).graphqlURL(); got != want {
+			t.Fatalf("want %q, got %q", want, got)
+		}
+	}
+}
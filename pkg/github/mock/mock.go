@@ -7,8 +7,15 @@ import (
 )
 
 type Getter struct {
-	FileHandler func(*github.File) error
-	RepoHandler func(*github.Repo) error
+	FileHandler  func(*github.File) error
+	RepoHandler  func(*github.Repo) error
+	TreeHandler  func(github.Repo, string) (github.Tree, error)
+	BlobHandler  func(github.Repo, string) ([]byte, error)
+	ReposHandler func(string) ([]github.RepoInfo, error)
+
+	TagsHandler          func(github.Repo) ([]github.Tag, error)
+	LatestReleaseHandler func(github.Repo) (github.Release, error)
+	CommitHandler        func(github.Repo, string) (github.Commit, error)
 }
 
 func (g Getter) GetFile(_ context.Context, f *github.File) error {
@@ -19,18 +26,55 @@ func (g Getter) GetRepo(_ context.Context, r *github.Repo) error {
 	return g.RepoHandler(r)
 }
 
+func (g Getter) GetTree(_ context.Context, r github.Repo, ref string) (github.Tree, error) {
+	return g.TreeHandler(r, ref)
+}
+
+func (g Getter) GetBlob(_ context.Context, r github.Repo, sha string) ([]byte, error) {
+	return g.BlobHandler(r, sha)
+}
+
+func (g Getter) ListRepos(_ context.Context, owner string) ([]github.RepoInfo, error) {
+	return g.ReposHandler(owner)
+}
+
+func (g Getter) ListTags(_ context.Context, r github.Repo) ([]github.Tag, error) {
+	return g.TagsHandler(r)
+}
+
+func (g Getter) GetLatestRelease(_ context.Context, r github.Repo) (github.Release, error) {
+	return g.LatestReleaseHandler(r)
+}
+
+func (g Getter) GetCommit(_ context.Context, r github.Repo, ref string) (github.Commit, error) {
+	return g.CommitHandler(r, ref)
+}
+
 type Updater struct {
-	Handler func(github.File, string, string) (github.File, error)
+	Handler func(github.Repo, github.Branch, string, []github.Change) (github.Commit, error)
 }
 
-func (g Updater) UpdateFile(_ context.Context, f github.File, head, msg string) (github.File, error) {
-	return g.Handler(f, head, msg)
+func (g Updater) CommitChanges(
+	_ context.Context,
+	r github.Repo,
+	b github.Branch,
+	msg string,
+	changes []github.Change,
+) (github.Commit, error) {
+	return g.Handler(r, b, msg, changes)
 }
 
 type GetterUpdater struct {
-	GetFileHandler func(*github.File) error
-	GetRepoHandler func(*github.Repo) error
-	UpdateHandler  func(github.File, string, string) (github.File, error)
+	GetFileHandler   func(*github.File) error
+	GetRepoHandler   func(*github.Repo) error
+	GetTreeHandler   func(github.Repo, string) (github.Tree, error)
+	GetBlobHandler   func(github.Repo, string) ([]byte, error)
+	ListReposHandler func(string) ([]github.RepoInfo, error)
+	ListTagsHandler  func(github.Repo) ([]github.Tag, error)
+	CommitHandler    func(github.Repo, github.Branch, string, []github.Change) (github.Commit, error)
+
+	GetLatestReleaseHandler func(github.Repo) (github.Release, error)
+	GetCommitHandler        func(github.Repo, string) (github.Commit, error)
 }
 
 func (g GetterUpdater) GetFile(_ context.Context, f *github.File) error {
@@ -41,6 +85,36 @@ func (g GetterUpdater) GetRepo(_ context.Context, r *github.Repo) error {
 	return g.GetRepoHandler(r)
 }
 
-func (g GetterUpdater) UpdateFile(_ context.Context, f github.File, head, msg string) (github.File, error) {
-	return g.UpdateHandler(f, head, msg)
+func (g GetterUpdater) GetTree(_ context.Context, r github.Repo, ref string) (github.Tree, error) {
+	return g.GetTreeHandler(r, ref)
+}
+
+func (g GetterUpdater) GetBlob(_ context.Context, r github.Repo, sha string) ([]byte, error) {
+	return g.GetBlobHandler(r, sha)
+}
+
+func (g GetterUpdater) ListRepos(_ context.Context, owner string) ([]github.RepoInfo, error) {
+	return g.ListReposHandler(owner)
+}
+
+func (g GetterUpdater) ListTags(_ context.Context, r github.Repo) ([]github.Tag, error) {
+	return g.ListTagsHandler(r)
+}
+
+func (g GetterUpdater) GetLatestRelease(_ context.Context, r github.Repo) (github.Release, error) {
+	return g.GetLatestReleaseHandler(r)
+}
+
+func (g GetterUpdater) GetCommit(_ context.Context, r github.Repo, ref string) (github.Commit, error) {
+	return g.GetCommitHandler(r, ref)
+}
+
+func (g GetterUpdater) CommitChanges(
+	_ context.Context,
+	r github.Repo,
+	b github.Branch,
+	msg string,
+	changes []github.Change,
+) (github.Commit, error) {
+	return g.CommitHandler(r, b, msg, changes)
 }
@@ -0,0 +1,47 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+)
+
+// perPage is the number of items per page, the maximum allowed by the API.
+const perPage = 100
+
+//nolint:gochecknoglobals // Used as a constant.
+var nextLinkRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// paginate gets all the items of a list, following the `Link: rel="next"`
+// headers from page to page. The path must contain a query string.
+// It returns the status of the last response.
+// https://docs.github.com/en/rest/using-the-rest-api/using-pagination-in-the-rest-api?apiVersion=2022-11-28
+func paginate[T any](ctx context.Context, g *GitHub, path string) ([]T, int, error) {
+	items := []T{}
+	url := g.endpoint + path
+
+	for url != "" {
+		page := []T{}
+
+		status, header, err := g.request(ctx, http.MethodGet, url, nil, &page)
+		if err != nil {
+			return nil, status, err
+		}
+
+		items = append(items, page...)
+		url = nextLink(header)
+	}
+
+	return items, http.StatusOK, nil
+}
+
+// nextLink returns the URL of the next page, if any.
+func nextLink(h http.Header) string {
+	for _, link := range h.Values("Link") {
+		if m := nextLinkRe.FindStringSubmatch(link); m != nil {
+			return m[1]
+		}
+	}
+
+	return ""
+}
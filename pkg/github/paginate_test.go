@@ -0,0 +1,133 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("follows the next links", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, "/items", nil)
+
+			if got := r.URL.Query().Get("per_page"); got != "2" {
+				t.Fatalf("want per_page to be kept, got %q", got)
+			}
+
+			next := func(page int) string {
+				return fmt.Sprintf(`<http://%s/items?per_page=2&page=%d>; rel="next"`, r.Host, page)
+			}
+			last := fmt.Sprintf(`<http://%s/items?per_page=2&page=3>; rel="last"`, r.Host)
+
+			switch r.URL.Query().Get("page") {
+			case "":
+				w.Header().Set("Link", next(2)+", "+last)
+				fmt.Fprint(w, `[1, 2]`)
+			case "2":
+				w.Header().Set("Link", next(3)+", "+last)
+				fmt.Fprint(w, `[3, 4]`)
+			case "3":
+				w.Header().Set("Link", fmt.Sprintf(`<http://%s/items?per_page=2&page=1>; rel="first"`, r.Host))
+				fmt.Fprint(w, `[5]`)
+			default:
+				t.Fatalf("unexpected page %q", r.URL.Query().Get("page"))
+			}
+		})
+
+		got, status, err := paginate[int](t.Context(), g, "/items?per_page=2")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if status != http.StatusOK {
+			t.Fatalf("want status %d, got %d", http.StatusOK, status)
+		}
+
+		if want := []int{1, 2, 3, 4, 5}; !slices.Equal(want, got) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("gets a single page", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `["a"]`)
+		})
+
+		got, _, err := paginate[string](t.Context(), g, "/items?per_page=2")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if want := []string{"a"}; !slices.Equal(want, got) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("fails on a page", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "2" {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/items?page=2>; rel="next"`, r.Host))
+			fmt.Fprint(w, `[1]`)
+		})
+
+		_, status, err := paginate[int](t.Context(), g, "/items?per_page=2")
+		if !errors.Is(err, ErrRequestFailed) {
+			t.Fatalf("want error %v, got %v", ErrRequestFailed, err)
+		}
+
+		if status != http.StatusNotFound {
+			t.Fatalf("want status %d, got %d", http.StatusNotFound, status)
+		}
+	})
+}
+
+func TestNextLink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		link string
+		want string
+	}{
+		{},
+		{link: `<https://api.test/a?page=3>; rel="last"`},
+		{
+			link: `<https://api.test/a?page=2>; rel="next", <https://api.test/a?page=3>; rel="last"`,
+			want: "https://api.test/a?page=2",
+		},
+		{
+			link: `<https://api.test/a?page=1>; rel="prev", <https://api.test/a?page=3>; rel="next"`,
+			want: "https://api.test/a?page=3",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			t.Parallel()
+
+			h := http.Header{}
+			if test.link != "" {
+				h.Set("Link", test.link)
+			}
+
+			if got := nextLink(h); got != test.want {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
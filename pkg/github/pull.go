@@ -15,10 +15,13 @@ var (
 	ErrGetPull    = errors.New("failed to get pull")
 	ErrCreatePull = errors.New("failed to create pull")
 	ErrPullExists = errors.New("pull already exist")
+	ErrUpdatePull = errors.New("failed to update pull")
 )
 
 type Pull struct {
-	Number int `json:"number"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
 
 	Repo Repo
 	New  bool
@@ -63,17 +66,24 @@ func (g *GitHub) GetPull(ctx context.Context, repo Repo, base, head string) (Pul
 }
 
 // https://docs.github.com/en/rest/pulls/pulls?apiVersion=2022-11-28#create-a-pull-request
-func (g *GitHub) CreatePull(ctx context.Context, repo Repo, base, head, title, pullBody string) (Pull, error) {
+func (g *GitHub) CreatePull(
+	ctx context.Context,
+	repo Repo,
+	base, head, title, pullBody string,
+	draft bool,
+) (Pull, error) {
 	body, err := json.Marshal(struct {
 		Title string `json:"title"`
 		Head  string `json:"head"`
 		Base  string `json:"base"`
 		Body  string `json:"body"`
+		Draft bool   `json:"draft,omitempty"`
 	}{
 		Title: title,
 		Body:  pullBody,
 		Head:  repo.Owner.Login + ":" + head,
 		Base:  base,
+		Draft: draft,
 	})
 	if err != nil {
 		return Pull{}, fmt.Errorf("%w: %w", ErrMarshalRequest, err)
@@ -93,7 +103,12 @@ func (g *GitHub) CreatePull(ctx context.Context, repo Repo, base, head, title, p
 	return pull, nil
 }
 
-func (g *GitHub) GetOrCreatePull(ctx context.Context, repo Repo, base, head, title, body string) (Pull, error) {
+func (g *GitHub) GetOrCreatePull(
+	ctx context.Context,
+	repo Repo,
+	base, head, title, body string,
+	draft bool,
+) (Pull, error) {
 	p, err := g.GetPull(ctx, repo, base, head)
 	if err == nil {
 		return p, nil
@@ -103,5 +118,83 @@ func (g *GitHub) GetOrCreatePull(ctx context.Context, repo Repo, base, head, tit
 		return Pull{}, err
 	}
 
-	return g.CreatePull(ctx, repo, base, head, title, body)
+	return g.CreatePull(ctx, repo, base, head, title, body, draft)
+}
+
+// https://docs.github.com/en/rest/issues/labels?apiVersion=2022-11-28#add-labels-to-an-issue
+func (g *GitHub) AddLabels(ctx context.Context, p Pull, labels []string) error {
+	path := fmt.Sprintf("/repos/%s/issues/%d/labels", p.Repo, p.Number)
+
+	return g.updatePull(ctx, http.MethodPost, path, struct {
+		Labels []string `json:"labels"`
+	}{Labels: labels})
+}
+
+// https://docs.github.com/en/rest/issues/assignees?apiVersion=2022-11-28#add-assignees-to-an-issue
+func (g *GitHub) AddAssignees(ctx context.Context, p Pull, assignees []string) error {
+	path := fmt.Sprintf("/repos/%s/issues/%d/assignees", p.Repo, p.Number)
+
+	return g.updatePull(ctx, http.MethodPost, path, struct {
+		Assignees []string `json:"assignees"`
+	}{Assignees: assignees})
+}
+
+// https://docs.github.com/en/rest/pulls/review-requests?apiVersion=2022-11-28#request-reviewers-for-a-pull-request
+func (g *GitHub) RequestReviewers(ctx context.Context, p Pull, reviewers, teamReviewers []string) error {
+	path := fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", p.Repo, p.Number)
+
+	return g.updatePull(ctx, http.MethodPost, path, struct {
+		Reviewers     []string `json:"reviewers,omitempty"`
+		TeamReviewers []string `json:"team_reviewers,omitempty"`
+	}{Reviewers: reviewers, TeamReviewers: teamReviewers})
+}
+
+// https://docs.github.com/en/rest/issues/issues?apiVersion=2022-11-28#update-an-issue
+func (g *GitHub) SetMilestone(ctx context.Context, p Pull, milestone int) error {
+	path := fmt.Sprintf("/repos/%s/issues/%d", p.Repo, p.Number)
+
+	return g.updatePull(ctx, http.MethodPatch, path, struct {
+		Milestone int `json:"milestone"`
+	}{Milestone: milestone})
+}
+
+// https://docs.github.com/en/rest/pulls/pulls?apiVersion=2022-11-28#update-a-pull-request
+func (g *GitHub) UpdatePull(ctx context.Context, p Pull, title, body string) error {
+	path := fmt.Sprintf("/repos/%s/pulls/%d", p.Repo, p.Number)
+
+	return g.updatePull(ctx, http.MethodPatch, path, struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}{Title: title, Body: body})
+}
+
+// https://docs.github.com/en/rest/pulls/pulls?apiVersion=2022-11-28#update-a-pull-request
+func (g *GitHub) ClosePull(ctx context.Context, p Pull) error {
+	path := fmt.Sprintf("/repos/%s/pulls/%d", p.Repo, p.Number)
+
+	return g.updatePull(ctx, http.MethodPatch, path, struct {
+		State string `json:"state"`
+	}{State: "closed"})
+}
+
+// https://docs.github.com/en/rest/issues/comments?apiVersion=2022-11-28#create-an-issue-comment
+func (g *GitHub) AddComment(ctx context.Context, p Pull, body string) error {
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", p.Repo, p.Number)
+
+	return g.updatePull(ctx, http.MethodPost, path, struct {
+		Body string `json:"body"`
+	}{Body: body})
+}
+
+func (g *GitHub) updatePull(ctx context.Context, method, path string, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	if _, err := g.req(ctx, method, path, bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrUpdatePull, err)
+	}
+
+	return nil
 }
@@ -77,7 +77,7 @@ func TestCreatePull(t *testing.T) {
 			w.WriteHeader(http.StatusUnprocessableEntity)
 		})
 
-		_, err := g.CreatePull(t.Context(), repo, "base", "head", "title", "body")
+		_, err := g.CreatePull(t.Context(), repo, "base", "head", "title", "body", false)
 		if !errors.Is(err, ErrPullExists) {
 			t.Fatalf("expected error %q, got %q", ErrPullExists, err)
 		}
@@ -97,7 +97,7 @@ func TestCreatePull(t *testing.T) {
 			fmt.Fprintf(w, `{"number": %d}\n`, number)
 		})
 
-		got, err := g.CreatePull(t.Context(), repo, base, head, title, body)
+		got, err := g.CreatePull(t.Context(), repo, base, head, title, body, false)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -114,6 +114,28 @@ func TestCreatePull(t *testing.T) {
 			t.Fatal("expected pull to be new, but it is not")
 		}
 	})
+
+	t.Run("create a draft pull", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r,
+				http.MethodPost,
+				pullAPIPath,
+				fmt.Appendf(nil,
+					`{"title":"%s","head":"%s:%s","base":"%s","body":"%s","draft":true}`,
+					title, repo.Owner.Login, head, base, body,
+				),
+			)
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"number": %d}\n`, number)
+		})
+
+		if _, err := g.CreatePull(t.Context(), repo, base, head, title, body, true); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
 }
 
 func TestGetOrCreatePull(t *testing.T) {
@@ -131,7 +153,7 @@ func TestGetOrCreatePull(t *testing.T) {
 			fmt.Fprintf(w, `[{"number": %d}]\n`, number)
 		})
 
-		got, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body)
+		got, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body, false)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -152,7 +174,7 @@ func TestGetOrCreatePull(t *testing.T) {
 			w.WriteHeader(http.StatusInternalServerError)
 		})
 
-		_, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body)
+		_, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body, false)
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -179,7 +201,7 @@ func TestGetOrCreatePull(t *testing.T) {
 			i++
 		})
 
-		got, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body)
+		got, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body, false)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -193,3 +215,105 @@ func TestGetOrCreatePull(t *testing.T) {
 		}
 	})
 }
+
+func TestUpdatePull(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number}
+	issuePath := fmt.Sprintf("/repos/owner/repo/issues/%d", number)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		update func(*GitHub) error
+	}{
+		{
+			name:   "adds labels",
+			method: http.MethodPost,
+			path:   issuePath + "/labels",
+			body:   `{"labels":["a","b"]}`,
+			update: func(g *GitHub) error { return g.AddLabels(t.Context(), pull, []string{"a", "b"}) },
+		},
+		{
+			name:   "adds assignees",
+			method: http.MethodPost,
+			path:   issuePath + "/assignees",
+			body:   `{"assignees":["a"]}`,
+			update: func(g *GitHub) error { return g.AddAssignees(t.Context(), pull, []string{"a"}) },
+		},
+		{
+			name:   "requests reviewers",
+			method: http.MethodPost,
+			path:   fmt.Sprintf("%s/%d/requested_reviewers", pullAPIPath, number),
+			body:   `{"reviewers":["a"],"team_reviewers":["t"]}`,
+			update: func(g *GitHub) error {
+				return g.RequestReviewers(t.Context(), pull, []string{"a"}, []string{"t"})
+			},
+		},
+		{
+			name:   "requests team reviewers only",
+			method: http.MethodPost,
+			path:   fmt.Sprintf("%s/%d/requested_reviewers", pullAPIPath, number),
+			body:   `{"team_reviewers":["t"]}`,
+			update: func(g *GitHub) error { return g.RequestReviewers(t.Context(), pull, nil, []string{"t"}) },
+		},
+		{
+			name:   "sets the milestone",
+			method: http.MethodPatch,
+			path:   issuePath,
+			body:   `{"milestone":3}`,
+			update: func(g *GitHub) error { return g.SetMilestone(t.Context(), pull, 3) },
+		},
+		{
+			name:   "updates the title and body",
+			method: http.MethodPatch,
+			path:   fmt.Sprintf("%s/%d", pullAPIPath, number),
+			body:   `{"title":"title","body":"body"}`,
+			update: func(g *GitHub) error { return g.UpdatePull(t.Context(), pull, title, body) },
+		},
+		{
+			name:   "closes the pull",
+			method: http.MethodPatch,
+			path:   fmt.Sprintf("%s/%d", pullAPIPath, number),
+			body:   `{"state":"closed"}`,
+			update: func(g *GitHub) error { return g.ClosePull(t.Context(), pull) },
+		},
+		{
+			name:   "adds a comment",
+			method: http.MethodPost,
+			path:   issuePath + "/comments",
+			body:   `{"body":"body"}`,
+			update: func(g *GitHub) error { return g.AddComment(t.Context(), pull, body) },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+				assertReq(t, r, test.method, test.path, []byte(test.body))
+				w.WriteHeader(http.StatusOK)
+			})
+
+			if err := test.update(g); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		})
+
+		err := g.AddLabels(t.Context(), pull, []string{"a"})
+		if !errors.Is(err, ErrUpdatePull) {
+			t.Fatalf("expected error %q, got %q", ErrUpdatePull, err)
+		}
+	})
+}
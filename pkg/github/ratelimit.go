@@ -0,0 +1,144 @@
+package github
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxRetries is the number of times a request is retried.
+	maxRetries = 3
+
+	// backoffBase is the first wait between retries, it doubles with each
+	// retry.
+	backoffBase = time.Second
+
+	// maxWait is the longest wait for a rate limit to reset, after which the
+	// request fails instead.
+	maxWait = 5 * time.Minute
+)
+
+// RateLimit is the API rate limit budget, as of the last response.
+// https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api?apiVersion=2022-11-28#checking-the-status-of-your-rate-limit
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// RateLimit returns the rate limit budget, as of the last response. It is
+// empty if no response had the rate limit headers.
+func (g *GitHub) RateLimit() RateLimit {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.rateLimit
+}
+
+func (g *GitHub) updateRateLimit(h http.Header) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	limit, _ := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.rateLimit = RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0),
+	}
+}
+
+// retryWait returns how long to wait before retrying the request, and whether
+// it can be retried at all.
+// Rate limited requests were not processed, so they are always retried.
+// Failed requests might have been processed, so only the idempotent ones are
+// retried.
+// https://docs.github.com/en/rest/using-the-rest-api/best-practices-for-using-the-rest-api?apiVersion=2022-11-28#handle-rate-limit-errors-appropriately
+func retryWait(method string, res *http.Response, err error, attempt int) (time.Duration, bool) {
+	switch {
+	case err != nil:
+		return backoff(attempt), idempotent(method)
+
+	case res.StatusCode == http.StatusTooManyRequests,
+		res.StatusCode == http.StatusForbidden && rateLimited(res.Header):
+		if wait, ok := resetWait(res.Header); ok {
+			return wait + jitter(backoffBase), true
+		}
+
+		return backoff(attempt), true
+
+	case res.StatusCode >= http.StatusInternalServerError:
+		return backoff(attempt), idempotent(method)
+
+	default:
+		return 0, false
+	}
+}
+
+// rateLimited tells apart the rate limit errors from the permission errors,
+// which share the 403 status.
+func rateLimited(h http.Header) bool {
+	return h.Get("Retry-After") != "" || h.Get("X-RateLimit-Remaining") == "0"
+}
+
+// resetWait returns how long to wait for the rate limit to reset, according to
+// the headers.
+func resetWait(h http.Header) (time.Duration, bool) {
+	if s, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		return time.Duration(s) * time.Second, true
+	}
+
+	if h.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+
+	reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(0, time.Until(time.Unix(reset, 0))), true
+}
+
+// backoff returns an exponential wait with jitter, so concurrent requests
+// don't retry all at once.
+func backoff(attempt int) time.Duration {
+	d := backoffBase << attempt
+
+	return d + jitter(d)
+}
+
+func jitter(d time.Duration) time.Duration {
+	return rand.N(d/2 + 1) //nolint:gosec // No need for a secure random here.
+}
+
+// https://developer.mozilla.org/en-US/docs/Glossary/Idempotent
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // The caller wraps it.
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+var (
+	ErrGetRelease = errors.New("failed to get release")
+	ErrNoRelease  = errors.New("no release found")
+	ErrListTags   = errors.New("failed to list tags")
+)
+
+type Release struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	HTMLURL string `json:"html_url"`
+}
+
+type Tag struct {
+	Name   string `json:"name"`
+	Commit Commit `json:"commit"`
+}
+
+// GetLatestRelease gets the latest published release, which is neither a
+// draft nor a prerelease.
+// https://docs.github.com/en/rest/releases/releases?apiVersion=2022-11-28#get-the-latest-release
+func (g *GitHub) GetLatestRelease(ctx context.Context, r Repo) (Release, error) {
+	log.DebugContext(ctx, "Get latest release", "repo", r)
+
+	rel := Release{}
+
+	status, err := g.req(ctx, http.MethodGet, r.APIPath()+"/releases/latest", nil, &rel)
+	if err != nil {
+		if status == http.StatusNotFound {
+			return Release{}, fmt.Errorf("%w for %s: %w", ErrNoRelease, r, err)
+		}
+
+		return Release{}, fmt.Errorf("%w for %s: %w", ErrGetRelease, r, err)
+	}
+
+	return rel, nil
+}
+
+// ListTags lists all the tags of a repository.
+// https://docs.github.com/en/rest/repos/repos?apiVersion=2022-11-28#list-repository-tags
+func (g *GitHub) ListTags(ctx context.Context, r Repo) ([]Tag, error) {
+	log.DebugContext(ctx, "List tags", "repo", r)
+
+	tags, _, err := paginate[Tag](ctx, g, fmt.Sprintf("%s/tags?per_page=%d", r.APIPath(), perPage))
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s: %w", ErrListTags, r, err)
+	}
+
+	return tags, nil
+}
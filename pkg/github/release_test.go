@@ -0,0 +1,103 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetLatestRelease(t *testing.T) {
+	t.Parallel()
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, "/repos/owner/repo/releases/latest", nil)
+
+			fmt.Fprint(w, `{"tag_name": "v1.2.3", "name": "Release 1.2.3"}`)
+		})
+
+		rel, err := g.GetLatestRelease(t.Context(), repo)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if rel.TagName != "v1.2.3" {
+			t.Fatalf("expected tag 'v1.2.3', got %q", rel.TagName)
+		}
+	})
+
+	t.Run("fails without release", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := g.GetLatestRelease(t.Context(), repo)
+		if !errors.Is(err, ErrNoRelease) {
+			t.Fatalf("expected error %v, got %v", ErrNoRelease, err)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		_, err := g.GetLatestRelease(t.Context(), repo)
+		if !errors.Is(err, ErrGetRelease) {
+			t.Fatalf("expected error %v, got %v", ErrGetRelease, err)
+		}
+	})
+}
+
+func TestListTags(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lists all the pages", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, "/repos/owner/repo/tags", nil)
+
+			if page := r.URL.Query().Get("page"); page == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=2>; rel="next"`, r.Host, r.URL.Path))
+				fmt.Fprintf(w, "[%s{}]", strings.Repeat(`{"name": "v0.0.1"},`, perPage-1))
+			} else {
+				fmt.Fprint(w, `[{"name": "v1.0.0", "commit": {"sha": "sha"}}]`)
+			}
+		})
+
+		tags, err := g.ListTags(t.Context(), repo)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(tags) != perPage+1 {
+			t.Fatalf("expected %d tags, got %d", perPage+1, len(tags))
+		}
+
+		if last := tags[perPage]; last.Name != "v1.0.0" || last.Commit.SHA != "sha" {
+			t.Fatalf("expected the last tag to be 'v1.0.0', got %+v", last)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		_, err := g.ListTags(t.Context(), repo)
+		if !errors.Is(err, ErrListTags) {
+			t.Fatalf("expected error %v, got %v", ErrListTags, err)
+		}
+	})
+}
@@ -15,7 +15,24 @@ type Repo struct {
 	DefaultBranch string `json:"default_branch"`
 }
 
-var errGetRepo = errors.New("failed to get repo")
+// RepoInfo is a repository as returned by the list endpoints, with the
+// metadata needed to filter it.
+type RepoInfo struct {
+	Name          string   `json:"name"`
+	Owner         User     `json:"owner"`
+	DefaultBranch string   `json:"default_branch"`
+	Topics        []string `json:"topics"`
+	Visibility    string   `json:"visibility"`
+	Language      string   `json:"language"`
+	Archived      bool     `json:"archived"`
+	Fork          bool     `json:"fork"`
+}
+
+var (
+	errGetRepo   = errors.New("failed to get repo")
+	ErrListRepos = errors.New("failed to list repos")
+	ErrNoOrg     = errors.New("organization not found")
+)
 
 func (r Repo) Equal(o Repo) bool {
 	return r.Repo == o.Repo && r.Owner.Login == o.Owner.Login
@@ -42,8 +59,50 @@ func (g *GitHub) GetRepo(ctx context.Context, r *Repo) error {
 	return nil
 }
 
+func (r RepoInfo) Repo() Repo {
+	return Repo{
+		Owner:         r.Owner,
+		Repo:          r.Name,
+		DefaultBranch: r.DefaultBranch,
+	}
+}
+
+// ListRepos lists all the repositories of an organization, or of a user if no
+// organization is found.
+// https://docs.github.com/en/rest/repos/repos?apiVersion=2022-11-28#list-organization-repositories
+// https://docs.github.com/en/rest/repos/repos?apiVersion=2022-11-28#list-repositories-for-a-user
+func (g *GitHub) ListRepos(ctx context.Context, owner string) ([]RepoInfo, error) {
+	log.DebugContext(ctx, "List repos", "owner", owner)
+
+	repos, err := g.listRepos(ctx, fmt.Sprintf("/orgs/%s/repos?type=all", owner))
+	if errors.Is(err, ErrNoOrg) {
+		log.DebugContext(ctx, "Organization not found, listing user repos", "owner", owner)
+
+		repos, err = g.listRepos(ctx, fmt.Sprintf("/users/%s/repos?type=owner", owner))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w for %q: %w", ErrListRepos, owner, err)
+	}
+
+	return repos, nil
+}
+
+func (g *GitHub) listRepos(ctx context.Context, path string) ([]RepoInfo, error) {
+	repos, status, err := paginate[RepoInfo](ctx, g, fmt.Sprintf("%s&per_page=%d", path, perPage))
+	if err != nil {
+		if status == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %w", ErrNoOrg, err)
+		}
+
+		return nil, err
+	}
+
+	return repos, nil
+}
+
 func (g *GitHub) GetDefaultBranchName(ctx context.Context, r Repo) (string, error) {
-	log.Debug("Get default branch name", "repo", r)
+	log.DebugContext(ctx, "Get default branch name", "repo", r)
 
 	err := g.GetRepo(ctx, &r)
 	if err != nil {
@@ -55,7 +114,7 @@ func (g *GitHub) GetDefaultBranchName(ctx context.Context, r Repo) (string, erro
 
 // https://docs.github.com/en/rest/branches/branches?apiVersion=2022-11-28#get-a-branch
 func (g *GitHub) GetDefaultBranch(ctx context.Context, r Repo) (Branch, error) {
-	log.Debug("Get default branch", "repo", r)
+	log.DebugContext(ctx, "Get default branch", "repo", r)
 
 	name, err := g.GetDefaultBranchName(ctx, r)
 	if err != nil {
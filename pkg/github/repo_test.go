@@ -1,8 +1,10 @@
 package github
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -115,3 +117,72 @@ func TestGetBaseAndHeadBranches(t *testing.T) {
 		t.Fatalf("expected branch to be new, got %v", head.New)
 	}
 }
+
+func TestListRepos(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lists all the pages", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, "/orgs/owner/repos", nil)
+
+			if page := r.URL.Query().Get("page"); page == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=2>; rel="next"`, r.Host, r.URL.Path))
+				fmt.Fprintf(w, "[%s{}]", strings.Repeat(`{"name": "a"},`, perPage-1))
+			} else {
+				fmt.Fprint(w, `[{"name": "b", "topics": ["t"]}]`)
+			}
+		})
+
+		repos, err := g.ListRepos(t.Context(), "owner")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(repos) != perPage+1 {
+			t.Fatalf("expected %d repos, got %d", perPage+1, len(repos))
+		}
+
+		if last := repos[perPage]; last.Name != "b" || last.Topics[0] != "t" {
+			t.Fatalf("expected the last repo to be 'b', got %+v", last)
+		}
+	})
+
+	t.Run("falls back to the user repos", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/orgs/owner/repos" {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			assertReq(t, r, http.MethodGet, "/users/owner/repos", nil)
+			fmt.Fprint(w, `[{"name": "a", "owner": {"login": "owner"}}]`)
+		})
+
+		repos, err := g.ListRepos(t.Context(), "owner")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(repos) != 1 || repos[0].Repo() != (Repo{Owner: User{Login: "owner"}, Repo: "a"}) {
+			t.Fatalf("expected the repo 'owner/a', got %+v", repos)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		_, err := g.ListRepos(t.Context(), "owner")
+		if !errors.Is(err, ErrListRepos) {
+			t.Fatalf("expected error %v, got %v", ErrListRepos, err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nobe4/gh-ln/pkg/log"
+)
+
+const (
+	TreeEntryBlob = "blob"
+	TreeEntryTree = "tree"
+)
+
+var (
+	ErrGetTree    = errors.New("failed to get tree")
+	ErrCreateTree = errors.New("failed to create tree")
+)
+
+type TreeEntry struct {
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+	Type string `json:"type"`
+	SHA  string `json:"sha"`
+	Size int    `json:"size"`
+}
+
+// NewTreeEntry is an entry of a tree to create. A nil SHA deletes the path.
+type NewTreeEntry struct {
+	Path string  `json:"path"`
+	Mode string  `json:"mode"`
+	Type string  `json:"type"`
+	SHA  *string `json:"sha"`
+}
+
+type Tree struct {
+	SHA       string      `json:"sha"`
+	Entries   []TreeEntry `json:"tree"`
+	Truncated bool        `json:"truncated"`
+}
+
+// Blobs returns the file entries of the tree, skipping directories and
+// submodules.
+func (t Tree) Blobs() []TreeEntry {
+	blobs := []TreeEntry{}
+
+	for _, e := range t.Entries {
+		if e.Type == TreeEntryBlob {
+			blobs = append(blobs, e)
+		}
+	}
+
+	return blobs
+}
+
+// https://docs.github.com/en/rest/git/trees?apiVersion=2022-11-28#get-a-tree
+func (g *GitHub) GetTree(ctx context.Context, r Repo, ref string) (Tree, error) {
+	log.DebugContext(ctx, "Get tree", "repo", r, "ref", ref)
+
+	t := Tree{}
+
+	path := fmt.Sprintf("/repos/%s/git/trees/%s?recursive=1", r, ref)
+
+	if _, err := g.req(ctx, http.MethodGet, path, nil, &t); err != nil {
+		return Tree{}, fmt.Errorf("%w: %w", ErrGetTree, err)
+	}
+
+	if t.Truncated {
+		log.WarnContext(ctx, "Tree is truncated, some files might be missing", "repo", r, "ref", ref)
+	}
+
+	return t, nil
+}
+
+// CreateTree creates a tree from the base tree with the entries changed, and
+// returns its SHA.
+// https://docs.github.com/en/rest/git/trees?apiVersion=2022-11-28#create-a-tree
+func (g *GitHub) CreateTree(ctx context.Context, r Repo, base string, entries []NewTreeEntry) (string, error) {
+	out := struct {
+		SHA string `json:"sha"`
+	}{}
+
+	if err := g.post(ctx, r.APIPath()+"/git/trees", struct {
+		BaseTree string         `json:"base_tree"`
+		Tree     []NewTreeEntry `json:"tree"`
+	}{
+		BaseTree: base,
+		Tree:     entries,
+	}, &out); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrCreateTree, err)
+	}
+
+	return out.SHA, nil
+}
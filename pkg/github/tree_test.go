@@ -0,0 +1,64 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const treeAPIPath = "/repos/owner/repo/git/trees/" + branch
+
+func TestGetTree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := g.GetTree(t.Context(), repo, branch)
+		if !errors.Is(err, ErrGetTree) {
+			t.Fatalf("expected error %v, got %v", ErrGetTree, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, treeAPIPath, nil)
+
+			if got := r.URL.Query().Get("recursive"); got != "1" {
+				t.Fatalf("expected recursive to be '1' but got '%s'", got)
+			}
+
+			fmt.Fprintf(w, `{"sha": "%s", "tree": [
+				{"path": "a", "type": "tree"},
+				{"path": "a/b", "type": "blob", "sha": "b"},
+				{"path": "c", "type": "commit"},
+				{"path": "d", "type": "blob", "sha": "d"}
+			]}`, sha)
+		})
+
+		tree, err := g.GetTree(t.Context(), repo, branch)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if tree.SHA != sha {
+			t.Fatalf("expected sha to be '%s' but got '%s'", sha, tree.SHA)
+		}
+
+		if len(tree.Entries) != 4 {
+			t.Fatalf("expected 4 entries but got %d", len(tree.Entries))
+		}
+
+		blobs := tree.Blobs()
+		if len(blobs) != 2 || blobs[0].Path != "a/b" || blobs[1].Path != "d" {
+			t.Fatalf("expected blobs 'a/b' and 'd' but got %+v", blobs)
+		}
+	})
+}
@@ -16,22 +16,34 @@ import (
 	contextfmt "github.com/nobe4/gh-ln/internal/format/context"
 	"github.com/nobe4/gh-ln/pkg/environment"
 	"github.com/nobe4/gh-ln/pkg/github"
+	"github.com/nobe4/gh-ln/pkg/github/cache"
 	"github.com/nobe4/gh-ln/pkg/log"
 )
 
+//nolint:gochecknoglobals // Used as a constant.
+var localRepo = github.Repo{
+	Owner: github.User{Login: "local_owner"},
+	Repo:  "local_repo",
+}
+
 func Run(ctx context.Context, e environment.Environment, g *github.GitHub) error {
 	c, err := getConfig(ctx, g, e)
 	if err != nil {
 		return err
 	}
 
-	f := contextfmt.New(c, e)
+	f := c.Format
 
 	groups := c.Links.Groups()
 
 	log.Debug("Processing groups", "groups", "\n"+groups.String())
 
-	if err := processGroups(ctx, g, f, groups); err != nil {
+	err = processGroups(ctx, g, f, c, groups, e.Concurrency)
+
+	rl := g.RateLimit()
+	log.Info("Rate limit", "remaining", rl.Remaining, "limit", rl.Limit, "reset", rl.Reset)
+
+	if err != nil {
 		return fmt.Errorf("failed to process the groups: %w", err)
 	}
 
@@ -45,12 +57,23 @@ func getConfig(ctx context.Context, g *github.GitHub, e environment.Environment)
 	}
 
 	c := config.New(source, e.Repo)
+	c.ReadInclude = includeReader(ctx, g, e)
+	c.Concurrency = e.Concurrency
+	c.Format = contextfmt.New(c, e)
 
 	if err := c.Parse(strings.NewReader(source.Content)); err != nil {
 		return nil, fmt.Errorf("failed to parse config %#v: %w", source, err)
 	}
 
-	if err := c.Populate(ctx, g); err != nil {
+	// NOTE: The sources don't change during the run, so they are read once.
+	cg := cache.New(g)
+	defer cg.LogStats(ctx)
+
+	if e.GraphQL {
+		cg.Batch = g
+	}
+
+	if err := c.Populate(ctx, cg); err != nil {
 		return nil, fmt.Errorf("failed to populate config: %w", err)
 	}
 
@@ -106,9 +129,26 @@ func readConfigFromFS(path string) (github.File, error) {
 		Ref:     "local_ref",
 		SHA:     "local_sha",
 
-		Repo: github.Repo{
-			Owner: github.User{Login: "local_owner"},
-			Repo:  "local_repo",
-		},
+		Repo: localRepo,
 	}, nil
 }
+
+// includeReader reads the included config files. With a local config, the
+// files without repository are read from the filesystem as well.
+func includeReader(ctx context.Context, g *github.GitHub, e environment.Environment) config.IncludeReader {
+	return func(f github.File) (github.File, error) {
+		if e.LocalConfig != "" && f.Repo.Equal(localRepo) {
+			return readConfigFromFS(f.Path)
+		}
+
+		log.Info("Get included config file", "file", f)
+
+		f.Path = strings.TrimPrefix(f.Path, "/")
+
+		if err := g.GetFile(ctx, &f); err != nil {
+			return github.File{}, fmt.Errorf("failed to get included config %#v: %w", f, err)
+		}
+
+		return f, nil
+	}
+}
@@ -1,19 +1,28 @@
 package ln
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/nobe4/gh-ln/internal/config"
 	"github.com/nobe4/gh-ln/internal/format"
+	"github.com/nobe4/gh-ln/internal/pool"
 	"github.com/nobe4/gh-ln/pkg/github"
 	"github.com/nobe4/gh-ln/pkg/log"
 )
 
+// obsoletePullComment explains why a pull request is closed.
+const obsoletePullComment = "The base branch already has the content of all the links, " +
+	"this pull request is not needed anymore. Closing it and deleting its branch."
+
+// Default pull settings, used when the group doesn't set them.
 const (
-	headName         = "auto-action-ln"
-	pullTitle        = "auto(ln): update links"
-	pullBodyTemplate = `
+	defaultPullTitle        = "auto(ln): update links"
+	defaultPullBodyTemplate = `
 {{/* This defines a backtick character to use in the markdown. */}}
 {{- $b := "` + "`" + `" -}}
 This automated PR updates the following files:
@@ -21,7 +30,7 @@ This automated PR updates the following files:
 | From | To  | Status |
 | ---  | --- | ---    |
 {{ range .Data -}}
-| [{{ $b }}{{ .From }}{{ $b }}]({{ .From.HTMLURL }}) | {{ $b }}{{ .To.Path }}{{ $b }} | {{ .Status }} |
+| [{{ $b }}{{ .From }}{{ $b }}]({{ .From.HTMLURL }}){{ with .RequestedRef }} ({{ $b }}{{ . }}{{ $b }}){{ end }} | {{ $b }}{{ .To.Path }}{{ $b }} | {{ .Status }} |
 {{ end }}
 
 ---
@@ -31,33 +40,193 @@ This automated PR updates the following files:
 `
 )
 
-func processGroups(ctx context.Context, g *github.GitHub, f format.Formatter, groups config.Groups) error {
-	for _, l := range groups {
-		err := processLinks(ctx, g, f, l)
+// processGroups processes the groups, sorted by repository, with at most
+// `concurrency` groups at once. A failing group doesn't stop the others, all
+// the failures are returned together.
+func processGroups(
+	ctx context.Context,
+	g *github.GitHub,
+	f format.Formatter,
+	c *config.Config,
+	groups config.Groups,
+	concurrency int,
+) error {
+	names := slices.Sorted(maps.Keys(groups))
+
+	errs := pool.Run(ctx, concurrency, len(names), func(ctx context.Context, i int) error {
+		l := groups[names[i]]
+
+		return processLinks(ctx, g, f, c.Group(l[0].To.Repo), l)
+	})
+
+	reportResult(names, groups, errs)
+
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = fmt.Errorf("%s: %w", names[i], err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// reportResult logs the outcome of each group.
+func reportResult(names []string, groups config.Groups, errs []error) {
+	log.Group("Result")
+	defer log.GroupEnd()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			log.Error("Group failed", "repo", name, "err", errs[i])
+
+			continue
+		}
+
+		log.Notice("Group processed", "repo", name, "links", len(groups[name]), "updated", len(updatedLinks(groups[name])))
+	}
+}
+
+// updatedLinks returns the links updated or deleted by the run.
+func updatedLinks(l config.Links) config.Links {
+	updated := config.Links{}
+
+	for _, link := range l {
+		if link.Status == config.StatusUpdated || link.Status == config.StatusDeleted {
+			updated = append(updated, link)
+		}
+	}
+
+	return updated
+}
+
+func processLinks(
+	ctx context.Context,
+	g *github.GitHub,
+	f format.Formatter,
+	group config.Group,
+	l config.Links,
+) error {
+	toRepo := l[0].To.Repo
+
+	log.GroupContext(ctx, "Processing links for "+toRepo.String())
+	defer log.GroupEndContext(ctx)
+
+	if group.Mode == config.ModeCommit {
+		committed, err := commitLinks(ctx, g, f, group, l)
 		if err != nil {
 			return err
 		}
+
+		if committed {
+			return nil
+		}
 	}
 
-	return nil
+	return pullLinks(ctx, g, f, group, l)
 }
 
-func processLinks(ctx context.Context, g *github.GitHub, f format.Formatter, l config.Links) error {
+// commitLinks commits the links directly to the group's branch. It reports
+// false if the branch doesn't allow it or the commit fails, so the links can be
+// updated through a pull request instead.
+func commitLinks(
+	ctx context.Context,
+	g *github.GitHub,
+	f format.Formatter,
+	group config.Group,
+	l config.Links,
+) (bool, error) {
 	toRepo := l[0].To.Repo
 
-	log.Group("Processing links for " + toRepo.String())
-	defer log.GroupEnd()
+	name := group.Branch
+	if name == "" {
+		var err error
+		if name, err = g.GetDefaultBranchName(ctx, toRepo); err != nil {
+			return false, fmt.Errorf("failed to get default branch: %w", err)
+		}
+	}
+
+	branch, err := g.GetBranch(ctx, toRepo, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to get branch %q: %w", name, err)
+	}
 
-	base, head, err := g.GetBaseAndHeadBranches(ctx, toRepo, headName)
+	canPush, err := g.CanPush(ctx, toRepo, branch)
+	if err != nil {
+		return false, fmt.Errorf("failed to check branch %q: %w", name, err)
+	}
+
+	if !canPush {
+		log.WarnContext(ctx, "Branch is protected, using a pull request instead", "repo", toRepo, "branch", name)
+
+		return false, nil
+	}
+
+	log.InfoContext(ctx, "Committing directly", "repo", toRepo, "branch", name)
+
+	// NOTE: The `to` files are compared and merged on the branch, they are
+	// restored if the links are updated through a pull request instead.
+	tos := make([]github.File, len(l))
+	for i, link := range l {
+		tos[i] = link.To
+	}
+
+	if err := l.PopulateToOn(ctx, g, branch); err != nil {
+		return false, fmt.Errorf("failed to read the links on branch %q: %w", name, err)
+	}
+
+	updated := l.Update(ctx, g, f, branch)
+	reportStatus(ctx, l)
+
+	if !updated && slices.ContainsFunc(l, failedToUpdate) {
+		log.WarnContext(ctx, "Failed to commit directly, using a pull request instead", "repo", toRepo, "branch", name)
+
+		for i, link := range l {
+			link.To = tos[i]
+		}
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// failedToUpdate reports whether the link couldn't be written, e.g. because the
+// commit was rejected by the branch's rules.
+func failedToUpdate(l *config.Link) bool {
+	return l.Status == config.StatusFailedToUpdate
+}
+
+func pullLinks(
+	ctx context.Context,
+	g *github.GitHub,
+	f format.Formatter,
+	group config.Group,
+	l config.Links,
+) error {
+	toRepo := l[0].To.Repo
+
+	base, head, err := g.GetBaseAndHeadBranches(ctx, toRepo, l[0].Head)
 	if err != nil {
 		return fmt.Errorf("failed to prepare branches: %w", err)
 	}
 
-	log.Debug("Parsed branches", "head", head, "base", base)
+	log.DebugContext(ctx, "Parsed branches", "head", head, "base", base)
 
 	updated := l.Update(ctx, g, f, head)
+
+	// NOTE: An existing head branch may already have some of the updates,
+	// they are checked against the base so the pull request still lists them.
+	needed := true
+	if !head.New {
+		if needed, err = l.NeedUpdateOn(ctx, g, base); err != nil {
+			return fmt.Errorf("failed to check base branch: %w", err)
+		}
+	}
+
+	reportStatus(ctx, l)
+
 	if !updated && head.New {
-		log.Info("No link was updated, cleaning up.", "repo", toRepo, "branch", head.Name)
+		log.InfoContext(ctx, "No link was updated, cleaning up.", "repo", toRepo, "branch", head.Name)
 
 		err = g.DeleteBranch(ctx, toRepo, head.Name)
 		if err != nil {
@@ -67,19 +236,145 @@ func processLinks(ctx context.Context, g *github.GitHub, f format.Formatter, l c
 		return nil
 	}
 
-	pullBody, err := f.Format(pullBodyTemplate, l)
+	if !updated && !needed {
+		return closeObsoletePull(ctx, g, toRepo, base, head)
+	}
+
+	pullTitle, err := f.Format(cmp.Or(group.Pull.Title, defaultPullTitle), l)
+	if err != nil {
+		return fmt.Errorf("failed to create pull request title: %w", err)
+	}
+
+	pullBody, err := f.Format(cmp.Or(group.Pull.Body, defaultPullBodyTemplate), l)
 	if err != nil {
 		return fmt.Errorf("failed to create pull request body: %w", err)
 	}
 
-	log.Debug("Pull body", "body", pullBody)
+	log.DebugContext(ctx, "Pull", "title", pullTitle, "body", pullBody)
 
-	pull, err := g.GetOrCreatePull(ctx, toRepo, base.Name, head.Name, pullTitle, pullBody)
+	pull, err := g.GetOrCreatePull(ctx, toRepo, base.Name, head.Name, pullTitle, pullBody, group.Pull.IsDraft())
 	if err != nil {
 		return fmt.Errorf("failed to get pull request: %w", err)
 	}
 
-	log.Info("Result pull request", "pull", pull, "new", pull.New)
+	log.InfoContext(ctx, "Result pull request", "pull", pull, "new", pull.New)
+
+	if pull.New {
+		if err := setupPull(ctx, g, pull, group.Pull); err != nil {
+			return fmt.Errorf("failed to set up pull request: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := refreshPull(ctx, g, f, pull, group.Pull, l, pullTitle, pullBody, updated); err != nil {
+		return fmt.Errorf("failed to refresh pull request: %w", err)
+	}
+
+	return nil
+}
+
+// refreshPull updates the title and body of an existing pull request if they
+// changed, so it shows the current state of the links, e.g. after the config
+// changed. A comment lists the links updated by the run, if it's configured
+// and some were.
+func refreshPull(
+	ctx context.Context,
+	g *github.GitHub,
+	f format.Formatter,
+	pull github.Pull,
+	p config.Pull,
+	l config.Links,
+	title, body string,
+	updated bool,
+) error {
+	if pull.Title == title && pull.Body == body {
+		log.DebugContext(ctx, "Pull request is up to date", "pull", pull)
+	} else {
+		if err := g.UpdatePull(ctx, pull, title, body); err != nil {
+			return err //nolint:wrapcheck // The error is descriptive enough.
+		}
+
+		log.InfoContext(ctx, "Refreshed pull request", "pull", pull)
+	}
+
+	if !updated || p.Comment == "" {
+		return nil
+	}
+
+	comment, err := f.Format(p.Comment, updatedLinks(l))
+	if err != nil {
+		return fmt.Errorf("failed to create pull request comment: %w", err)
+	}
+
+	return g.AddComment(ctx, pull, comment) //nolint:wrapcheck // The error is descriptive enough.
+}
+
+// closeObsoletePull closes the pull request of a head branch that has nothing
+// left to update, with a comment, and deletes the branch.
+func closeObsoletePull(ctx context.Context, g *github.GitHub, r github.Repo, base, head github.Branch) error {
+	log.InfoContext(ctx, "Base branch is up to date, closing the pull request.", "repo", r, "branch", head.Name)
+
+	pull, err := g.GetPull(ctx, r, base.Name, head.Name)
+
+	switch {
+	case errors.Is(err, github.ErrNoPull):
+		log.DebugContext(ctx, "No pull request to close", "repo", r, "branch", head.Name)
+
+	case err != nil:
+		return fmt.Errorf("failed to get pull request: %w", err)
+
+	default:
+		if err := g.AddComment(ctx, pull, obsoletePullComment); err != nil {
+			return fmt.Errorf("failed to comment on obsolete pull request: %w", err)
+		}
+
+		if err := g.ClosePull(ctx, pull); err != nil {
+			return fmt.Errorf("failed to close obsolete pull request: %w", err)
+		}
+
+		log.NoticeContext(ctx, "Closed obsolete pull request", "pull", pull)
+	}
+
+	if err := g.DeleteBranch(ctx, r, head.Name); err != nil {
+		return fmt.Errorf("failed to delete obsolete branch: %w", err)
+	}
+
+	return nil
+}
+
+// reportStatus logs the status of each link.
+func reportStatus(ctx context.Context, l config.Links) {
+	for _, link := range l {
+		log.NoticeContext(ctx, "Link status", "link", link, "status", link.Status)
+	}
+}
+
+// setupPull applies the settings to a newly created pull request.
+func setupPull(ctx context.Context, g *github.GitHub, pull github.Pull, p config.Pull) error {
+	if len(p.Labels) > 0 {
+		if err := g.AddLabels(ctx, pull, p.Labels); err != nil {
+			return err //nolint:wrapcheck // The error is descriptive enough.
+		}
+	}
+
+	if len(p.Assignees) > 0 {
+		if err := g.AddAssignees(ctx, pull, p.Assignees); err != nil {
+			return err //nolint:wrapcheck // The error is descriptive enough.
+		}
+	}
+
+	if len(p.Reviewers) > 0 || len(p.TeamReviewers) > 0 {
+		if err := g.RequestReviewers(ctx, pull, p.Reviewers, p.TeamReviewers); err != nil {
+			return err //nolint:wrapcheck // The error is descriptive enough.
+		}
+	}
+
+	if p.Milestone > 0 {
+		if err := g.SetMilestone(ctx, pull, p.Milestone); err != nil {
+			return err //nolint:wrapcheck // The error is descriptive enough.
+		}
+	}
 
 	return nil
 }
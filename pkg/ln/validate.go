@@ -0,0 +1,54 @@
+package ln
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nobe4/gh-ln/internal/config"
+	"github.com/nobe4/gh-ln/pkg/environment"
+)
+
+var errInvalidConfig = errors.New("invalid config")
+
+// Validate checks the local config files given as arguments, or the local
+// config, without calling the API. Each problem is written to w as
+// `path:line:column: message`.
+func Validate(w io.Writer, e environment.Environment) error {
+	paths := e.Args
+	if len(paths) == 0 {
+		paths = []string{e.LocalConfig}
+	}
+
+	if paths[0] == "" {
+		paths[0] = e.Config
+	}
+
+	repo := e.Repo
+	if repo.Empty() {
+		repo = localRepo
+	}
+
+	count := 0
+
+	for _, path := range paths {
+		source, err := readConfigFromFS(path)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range config.Validate(source, repo) {
+			count++
+
+			if _, err := fmt.Fprintf(w, "%s:%s\n", path, p); err != nil {
+				return fmt.Errorf("failed to write problem: %w", err)
+			}
+		}
+	}
+
+	if count > 0 {
+		return fmt.Errorf("%w: found %d problem(s)", errInvalidConfig, count)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+//nolint:gochecknoglobals // Serializes the flushes, so buffers don't interleave.
+var flushMu sync.Mutex
+
+type bufferKey struct{}
+
+// Buffer holds the records logged with a context, to write them together
+// later. It keeps the logs of concurrent tasks from interleaving.
+type Buffer struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+// WithBuffer returns a context whose logs are kept in the returned buffer
+// until it is flushed.
+func WithBuffer(ctx context.Context) (context.Context, *Buffer) {
+	b := &Buffer{}
+
+	return context.WithValue(ctx, bufferKey{}, b), b
+}
+
+func bufferFrom(ctx context.Context) *Buffer {
+	b, _ := ctx.Value(bufferKey{}).(*Buffer)
+
+	return b
+}
+
+func (b *Buffer) add(ctx context.Context, level slog.Level, msg string, attrs ...any) {
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r.Add(attrs...)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.records = append(b.records, r)
+}
+
+// Flush writes the buffered records to the default logger, without
+// interleaving with other flushes, and empties the buffer.
+func (b *Buffer) Flush(ctx context.Context) {
+	b.mu.Lock()
+	records := b.records
+	b.records = nil
+	b.mu.Unlock()
+
+	flushMu.Lock()
+	defer flushMu.Unlock()
+
+	h := slog.Default().Handler()
+
+	for _, r := range records {
+		//nolint:errcheck // Logging errors are ignored, like with slog.Log.
+		h.Handle(ctx, r)
+	}
+}
@@ -32,29 +32,69 @@ type Options struct {
 }
 
 func Info(msg string, attrs ...any) {
-	slog.Info(msg, attrs...)
+	InfoContext(context.Background(), msg, attrs...)
 }
 
 func Debug(msg string, attrs ...any) {
-	slog.Debug(msg, attrs...)
+	DebugContext(context.Background(), msg, attrs...)
 }
 
 func Error(msg string, attrs ...any) {
-	slog.Error(msg, attrs...)
+	ErrorContext(context.Background(), msg, attrs...)
 }
 
 func Warn(msg string, attrs ...any) {
-	slog.Warn(msg, attrs...)
+	WarnContext(context.Background(), msg, attrs...)
 }
 
 func Notice(msg string, attrs ...any) {
-	slog.Log(context.Background(), LevelNotice, msg, attrs...)
+	NoticeContext(context.Background(), msg, attrs...)
 }
 
 func Group(name string) {
-	slog.Log(context.Background(), LevelGroup, name)
+	GroupContext(context.Background(), name)
 }
 
 func GroupEnd() {
-	slog.Log(context.Background(), LevelGroupEnd, "")
+	GroupEndContext(context.Background())
+}
+
+// The *Context variants log into the context's buffer, if any, see WithBuffer.
+
+func InfoContext(ctx context.Context, msg string, attrs ...any) {
+	logContext(ctx, LevelInfo, msg, attrs...)
+}
+
+func DebugContext(ctx context.Context, msg string, attrs ...any) {
+	logContext(ctx, LevelDebug, msg, attrs...)
+}
+
+func ErrorContext(ctx context.Context, msg string, attrs ...any) {
+	logContext(ctx, LevelError, msg, attrs...)
+}
+
+func WarnContext(ctx context.Context, msg string, attrs ...any) {
+	logContext(ctx, LevelWarn, msg, attrs...)
+}
+
+func NoticeContext(ctx context.Context, msg string, attrs ...any) {
+	logContext(ctx, LevelNotice, msg, attrs...)
+}
+
+func GroupContext(ctx context.Context, name string) {
+	logContext(ctx, LevelGroup, name)
+}
+
+func GroupEndContext(ctx context.Context) {
+	logContext(ctx, LevelGroupEnd, "")
+}
+
+func logContext(ctx context.Context, level slog.Level, msg string, attrs ...any) {
+	if b := bufferFrom(ctx); b != nil {
+		b.add(ctx, level, msg, attrs...)
+
+		return
+	}
+
+	slog.Log(ctx, level, msg, attrs...)
 }